@@ -0,0 +1,121 @@
+package goadder
+
+import (
+	"sync/atomic"
+)
+
+const defaultPromoteThreshold = 64
+
+// WithPromoteThreshold sets the number of failed CAS within an interval after which AdaptiveAdder promotes
+// itself to striped representation. Non-positive value keeps the default of 64. Other adders ignore this option.
+func WithPromoteThreshold(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.promoteThreshold = uint32(n)
+		}
+	}
+}
+
+// AdaptiveAdder starts as a plain atomic counter, like AtomicAdder, and counts failed CAS on it.
+// Once failures reach a threshold, see WithPromoteThreshold, it promotes itself to striped
+// representation of JDKAdder, spreading updates over Cells.
+//
+// Compact demotes the adder back to atomic counter, folding Cells into base. To demote after a quiet
+// period, add the adder to a Compactor: it is compacted once its Cells stayed idle for a whole interval
+// of the Compactor. Failures are then counted per interval of the Compactor, so that rare contention
+// spread over a long time does not promote the adder.
+//
+// The atomic counter is base of the striped representation, so Sum is correct throughout transitions:
+// an update is either made to base or to a Cell, both are always summed.
+//
+// AdaptiveAdder keeps contention counters, see Stats. It is high performance, non-blocking and safe for
+// concurrent use.
+type AdaptiveAdder struct {
+	JDKAdder
+	promoted  int32
+	failures  uint32
+	threshold uint32
+}
+
+// NewAdaptiveAdder create new AdaptiveAdder with given options.
+func NewAdaptiveAdder(opts ...Option) *AdaptiveAdder {
+	opts = append(opts[:len(opts):len(opts)], WithContentionStats())
+
+	a := &AdaptiveAdder{}
+	a.init(opts)
+	if a.threshold = a.conf.promoteThreshold; a.threshold == 0 {
+		a.threshold = defaultPromoteThreshold
+	}
+	return a
+}
+
+// Add the given value
+func (a *AdaptiveAdder) Add(x int64) {
+	if atomic.LoadInt32(&a.promoted) == 0 {
+		for {
+			b := a.base.load()
+			if a.wraps(b, b+x, nil, x < 0) {
+				a.onWrap(x, x < 0)
+				return
+			}
+			if a.base.cas(b, b+x) {
+				return
+			}
+			if a.contended() {
+				break
+			}
+		}
+	}
+	a.JDKAdder.Add(x)
+}
+
+// contended records a failed CAS on base, promoting the adder once failures reach threshold.
+func (a *AdaptiveAdder) contended() bool {
+	a.contention.baseCASFailed()
+	if atomic.AddUint32(&a.failures, 1) < a.threshold {
+		return false
+	}
+	atomic.StoreInt32(&a.promoted, 1)
+	return true
+}
+
+// addProbe adds x using the given probe once promoted.
+func (a *AdaptiveAdder) addProbe(x int64, probe int) {
+	if atomic.LoadInt32(&a.promoted) == 0 {
+		a.Add(x)
+	} else {
+		a.JDKAdder.addProbe(x, probe)
+	}
+}
+
+// Inc by 1
+func (a *AdaptiveAdder) Inc() {
+	a.Add(1)
+}
+
+// Dec by 1
+func (a *AdaptiveAdder) Dec() {
+	a.Add(-1)
+}
+
+// Promoted reports whether the adder is in striped representation.
+func (a *AdaptiveAdder) Promoted() bool {
+	return atomic.LoadInt32(&a.promoted) != 0
+}
+
+// Compact demotes the adder to atomic counter, folds all cells into base and shrinks cell table
+// back to its initial size. The sum is unchanged and it is safe to call concurrently with updates.
+func (a *AdaptiveAdder) Compact() {
+	atomic.StoreInt32(&a.promoted, 0)
+	atomic.StoreUint32(&a.failures, 0)
+	a.JDKAdder.Compact()
+}
+
+// fingerprint digests values of cells, the adder is populated while promoted. So Compactor
+// demotes the adder after an interval without updates to cells. Compactor takes fingerprint
+// once per tick, which also starts a new interval of failures.
+func (a *AdaptiveAdder) fingerprint() (uint64, bool) {
+	atomic.StoreUint32(&a.failures, 0)
+	h, _ := a.JDKAdder.fingerprint()
+	return h, a.Promoted()
+}
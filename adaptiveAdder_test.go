@@ -0,0 +1,161 @@
+package goadder
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveAdderNotRaceInc(t *testing.T) {
+	testAdderNotRaceInc(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderRaceInc(t *testing.T) {
+	testAdderRaceInc(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderNotRaceDec(t *testing.T) {
+	testAdderNotRaceDec(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderRaceDec(t *testing.T) {
+	testAdderRaceDec(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderNotRaceAdd(t *testing.T) {
+	testAdderNotRaceAdd(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderRaceAdd(t *testing.T) {
+	testAdderRaceAdd(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderRaceDrain(t *testing.T) {
+	testAdderRaceDrain(t, AdaptiveAdderType)
+}
+
+func TestAdaptiveAdderPromote(t *testing.T) {
+	adder := NewAdaptiveAdder(WithPromoteThreshold(3))
+	adder.Add(5)
+
+	for i := 0; i < 2; i++ {
+		if adder.contended(); adder.Promoted() {
+			t.Errorf("AdaptiveAdder must not be promoted below threshold")
+		}
+	}
+	if adder.contended(); !adder.Promoted() {
+		t.Errorf("AdaptiveAdder must be promoted at threshold")
+	}
+
+	// contention spreads updates over cells
+	adder.accumulate(getRandomInt(), 7, nil, false, true)
+	if adder.Stats().PopulatedCells == 0 || adder.Sum() != 12 {
+		t.Errorf("AdaptiveAdder logic is wrong")
+	}
+
+	adder.Compact()
+	if adder.Promoted() || adder.Stats().PopulatedCells != 0 || adder.Sum() != 12 {
+		t.Errorf("AdaptiveAdder must be demoted by Compact")
+	}
+}
+
+func TestAdaptiveAdderDemoteWhenQuiet(t *testing.T) {
+	adder := NewAdaptiveAdder(WithPromoteThreshold(1))
+	adder.contended()
+
+	compactor := NewCompactor(10 * time.Millisecond)
+	defer compactor.Stop()
+	compactor.Add(adder)
+
+	// still updated, but without contention
+	for deadline := time.Now().Add(5 * time.Second); adder.Promoted() && time.Now().Before(deadline); {
+		adder.Inc()
+		time.Sleep(time.Millisecond)
+	}
+	if adder.Promoted() {
+		t.Errorf("AdaptiveAdder must be demoted after a quiet period")
+	}
+}
+
+func TestAdaptiveAdderStayPromotedWhenContended(t *testing.T) {
+	c := NewCompactor(time.Hour)
+	defer c.Stop()
+
+	adder := NewAdaptiveAdder(WithPromoteThreshold(1))
+	adder.contended()
+	c.Add(adder)
+
+	// sustained contention keeps updating cells, while CAS on them rarely fail
+	for i := 0; i < 10; i++ {
+		adder.accumulate(getRandomInt(), 1, nil, false, true)
+		c.tick()
+		if !adder.Promoted() {
+			t.Fatalf("AdaptiveAdder must stay promoted under contention, demoted at tick %d", i)
+		}
+	}
+
+	c.tick()
+	if adder.Promoted() || adder.Sum() != 10 {
+		t.Errorf("AdaptiveAdder must be demoted after a quiet interval")
+	}
+}
+
+func TestAdaptiveAdderFailuresWindow(t *testing.T) {
+	c := NewCompactor(time.Hour)
+	defer c.Stop()
+
+	adder := NewAdaptiveAdder(WithPromoteThreshold(3))
+	c.Add(adder)
+
+	// rare contention spread over intervals never promotes
+	for i := 0; i < 10; i++ {
+		adder.contended()
+		adder.contended()
+		c.tick()
+	}
+	if adder.Promoted() {
+		t.Errorf("AdaptiveAdder must count failures per interval")
+	}
+
+	adder.contended()
+	adder.contended()
+	if adder.contended(); !adder.Promoted() {
+		t.Errorf("AdaptiveAdder must be promoted at threshold within an interval")
+	}
+}
+
+func TestAdaptiveAdderRaceTransitions(t *testing.T) {
+	adder := NewAdaptiveAdder(WithPromoteThreshold(1))
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Add(2)
+				adder.Dec()
+			}
+			wg.Done()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				adder.contended()
+				adder.Compact()
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	if expected := int64(delta/10) * int64(numRoutine); adder.Sum() != expected {
+		t.Errorf("AdaptiveAdder logic is wrong: %d, expected %d", adder.Sum(), expected)
+	}
+}
@@ -0,0 +1,198 @@
+package goadder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// AdderMap is a concurrent map of LongAdders, one per key such as endpoint, tenant or status code.
+// Adders are created lazily upon first update of their key.
+//
+// Updating an existing key is lock-free, only creation of a new key takes a lock. The number of keys
+// could be capped: once reached, updates of new keys go to a single overflow key instead, so a burst
+// of unexpected keys could not blow up memory. Updates of new keys are lock-free while the map is full.
+//
+// AdderMap is safe for concurrent use.
+type AdderMap[K comparable] struct {
+	// keys is the number of keys, excluding overflow key. It is the first field so that it is
+	// 64-bit aligned for atomic operations on 32-bit platforms.
+	keys int64
+
+	t           Type
+	opts        []Option
+	maxKeys     int
+	overflowKey K
+
+	adders   sync.Map // K -> LongAdder
+	creator  sync.Mutex
+	full     int32        // set once maxKeys is reached, cleared by Delete
+	overflow atomic.Value // LongAdder of overflow key, valid while full is set
+}
+
+// NewAdderMap create new AdderMap whose adders are created upon type with given options, see NewLongAdder.
+// If maxKeys is positive, at most maxKeys keys are created, updates of other keys go to overflowKey.
+func NewAdderMap[K comparable](t Type, maxKeys int, overflowKey K, opts ...Option) *AdderMap[K] {
+	return &AdderMap[K]{
+		t:           t,
+		opts:        opts,
+		maxKeys:     maxKeys,
+		overflowKey: overflowKey,
+	}
+}
+
+// Add the given value to adder of key.
+func (m *AdderMap[K]) Add(key K, x int64) {
+	m.Get(key).Add(x)
+}
+
+// Inc adder of key by 1
+func (m *AdderMap[K]) Inc(key K) {
+	m.Get(key).Inc()
+}
+
+// Dec adder of key by 1
+func (m *AdderMap[K]) Dec(key K) {
+	m.Get(key).Dec()
+}
+
+// Get returns adder of key, creating it if needed. If the map is full,
+// adder of overflow key is returned instead.
+func (m *AdderMap[K]) Get(key K) LongAdder {
+	if a, ok := m.adders.Load(key); ok {
+		return a.(LongAdder)
+	}
+	if atomic.LoadInt32(&m.full) != 0 {
+		return m.overflow.Load().(LongAdder)
+	}
+	return m.create(key)
+}
+
+func (m *AdderMap[K]) create(key K) LongAdder {
+	m.creator.Lock()
+	defer m.creator.Unlock()
+
+	if a, ok := m.adders.Load(key); ok {
+		return a.(LongAdder)
+	}
+
+	if key != m.overflowKey {
+		if m.maxKeys > 0 && atomic.LoadInt64(&m.keys) >= int64(m.maxKeys) {
+			return m.fill()
+		}
+		atomic.AddInt64(&m.keys, 1)
+	}
+
+	a := NewLongAdder(m.t, m.opts...)
+	m.adders.Store(key, a)
+	return a
+}
+
+// fill marks the map full, so that updates of new keys go to overflow key without lock.
+// It returns adder of overflow key, creating it if needed. Caller must hold creator.
+func (m *AdderMap[K]) fill() LongAdder {
+	a, ok := m.adders.Load(m.overflowKey)
+	if !ok {
+		a = NewLongAdder(m.t, m.opts...)
+		m.adders.Store(m.overflowKey, a)
+	}
+	m.overflow.Store(a)
+	atomic.StoreInt32(&m.full, 1)
+	return a.(LongAdder)
+}
+
+// Delete key and returns sum of its adder. Updates of key concurrent with Delete might be lost.
+func (m *AdderMap[K]) Delete(key K) (sum int64, ok bool) {
+	m.creator.Lock()
+	a, ok := m.adders.LoadAndDelete(key)
+	if ok {
+		if key != m.overflowKey {
+			atomic.AddInt64(&m.keys, -1)
+		}
+		atomic.StoreInt32(&m.full, 0)
+	}
+	m.creator.Unlock()
+
+	if ok {
+		sum = a.(LongAdder).Sum()
+	}
+	return
+}
+
+// Len returns number of keys, including overflow key if it was updated.
+func (m *AdderMap[K]) Len() (n int) {
+	m.adders.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return
+}
+
+// Range calls f for each key and its adder. If f returns false, Range stops.
+// See sync.Map.Range for consistency guarantee.
+func (m *AdderMap[K]) Range(f func(key K, adder LongAdder) bool) {
+	m.adders.Range(func(k, a interface{}) bool {
+		return f(k.(K), a.(LongAdder))
+	})
+}
+
+// Snapshot returns sum of each key. Like Sum of each adder, it is NOT an atomic snapshot.
+func (m *AdderMap[K]) Snapshot() map[K]int64 {
+	s := make(map[K]int64)
+	m.Range(func(key K, adder LongAdder) bool {
+		s[key] = adder.Sum()
+		return true
+	})
+	return s
+}
+
+// SumAll returns sum of all keys.
+func (m *AdderMap[K]) SumAll() (sum int64) {
+	m.Range(func(_ K, adder LongAdder) bool {
+		sum += adder.Sum()
+		return true
+	})
+	return
+}
+
+// DrainAll returns sum of each key and resets it, see SumAndReset. Keys are kept, so that their adders
+// are reused. An update concurrent with DrainAll is counted in exactly one of the returned map or
+// subsequent ones, for adders whose SumAndReset is safe on a live adder.
+func (m *AdderMap[K]) DrainAll() map[K]int64 {
+	s := make(map[K]int64)
+	m.Range(func(key K, adder LongAdder) bool {
+		s[key] = adder.SumAndReset()
+		return true
+	})
+	return s
+}
+
+// String returns sum of each key as JSON object, keys formatted by fmt.Sprint in order.
+func (m *AdderMap[K]) String() string {
+	sums := make(map[string]int64)
+	m.Range(func(key K, adder LongAdder) bool {
+		sums[fmt.Sprint(key)] += adder.Sum()
+		return true
+	})
+
+	keys := make([]string, 0, len(sums))
+	for k := range sums {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(quoteJSON(k))
+		sb.WriteString(": ")
+		sb.WriteString(formatJSON(sums[k]))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
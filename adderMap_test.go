@@ -0,0 +1,140 @@
+package goadder
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdderMapRace(t *testing.T) {
+	for _, ty := range []Type{JDKAdderType, RandomCellAdderType, AtomicAdderType, PerPAdderType} {
+		m := NewAdderMap[string](ty, 0, "")
+
+		var wg sync.WaitGroup
+		for i := 0; i < numRoutine; i++ {
+			wg.Add(1)
+			go func() {
+				for j := 0; j < delta/100; j++ {
+					m.Add(fmt.Sprint(j%16), 2)
+					m.Dec(fmt.Sprint(j % 16))
+				}
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+
+		expected := int64(delta/100) * int64(numRoutine)
+		if m.Len() != 16 || m.SumAll() != expected {
+			t.Errorf("AdderMap(%d) logic is wrong", ty)
+		}
+
+		var sum int64
+		for _, v := range m.DrainAll() {
+			sum += v
+		}
+		if sum != expected || m.SumAll() != 0 || m.Len() != 16 {
+			t.Errorf("AdderMap(%d) logic is wrong", ty)
+		}
+	}
+}
+
+func TestAdderMapMaxKeys(t *testing.T) {
+	m := NewAdderMap[int](JDKAdderType, 3, -1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for k := 0; k < 10; k++ {
+				m.Inc(k)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	s := m.Snapshot()
+	if len(s) != 4 || s[-1] != int64(7*numRoutine) {
+		t.Errorf("AdderMap must cap number of keys: %v", s)
+	}
+	for k, v := range s {
+		if k != -1 && v != int64(numRoutine) {
+			t.Errorf("AdderMap logic is wrong: %v", s)
+		}
+	}
+
+	// deleting a key makes room for another one
+	var deleted int
+	m.Range(func(k int, _ LongAdder) bool {
+		deleted = k
+		return k == -1
+	})
+	if sum, ok := m.Delete(deleted); !ok || sum != int64(numRoutine) {
+		t.Errorf("Delete logic is wrong")
+	}
+	if _, ok := m.Delete(deleted); ok {
+		t.Errorf("Delete logic is wrong")
+	}
+
+	m.Add(100, 5)
+	if s = m.Snapshot(); len(s) != 4 || s[100] != 5 {
+		t.Errorf("AdderMap logic is wrong: %v", s)
+	}
+	if m.Add(101, 5); m.Snapshot()[-1] != int64(7*numRoutine)+5 {
+		t.Errorf("AdderMap must cap number of keys")
+	}
+}
+
+func TestAdderMapOverflowLockFree(t *testing.T) {
+	m := NewAdderMap[int](AtomicAdderType, 2, -1)
+	m.Inc(0)
+	m.Inc(1)
+	m.Inc(2)
+
+	// once full, updates of new keys do not wait for creator
+	m.creator.Lock()
+	done := make(chan struct{})
+	go func() {
+		for k := 3; k < 100; k++ {
+			m.Inc(k)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Overflowing updates must not take lock")
+	}
+	m.creator.Unlock()
+
+	if s := m.Snapshot(); len(s) != 3 || s[-1] != 98 {
+		t.Errorf("AdderMap must cap number of keys: %v", s)
+	}
+
+	// deleting overflow key or another key makes the map not full anymore
+	if sum, ok := m.Delete(-1); !ok || sum != 98 {
+		t.Errorf("Delete logic is wrong")
+	}
+	if m.Inc(100); m.Snapshot()[-1] != 1 {
+		t.Errorf("AdderMap must recreate overflow key")
+	}
+	m.Delete(0)
+	if m.Inc(101); m.Snapshot()[101] != 1 {
+		t.Errorf("Deleting a key must make room for another one")
+	}
+}
+
+func BenchmarkAdderMapOverflow(b *testing.B) {
+	m := NewAdderMap[int](JDKAdderType, 16, -1)
+	for k := 0; k < 16; k++ {
+		m.Inc(k)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for k := 16; pb.Next(); k++ {
+			m.Inc(k)
+		}
+	})
+}
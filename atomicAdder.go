@@ -6,17 +6,23 @@ import (
 
 // AtomicAdder is simple atomic-based adder. Fastest at single routine but slow at multi routine when high-contention happens.
 type AtomicAdder struct {
-	value int64
+	atomicValue[int64]
 }
 
 // NewAtomicAdder create new AtomicAdder
-func NewAtomicAdder() *AtomicAdder {
-	return &AtomicAdder{}
+func NewAtomicAdder(opts ...Option) *AtomicAdder {
+	a := &AtomicAdder{}
+	a.init(opts)
+	return a
 }
 
 // Add the given value
 func (a *AtomicAdder) Add(x int64) {
-	atomic.AddInt64(&a.value, x)
+	if a.overflow.enabled {
+		a.addChecked(x, x < 0)
+		return
+	}
+	atomic.AddUint64(&a.value, uint64(x))
 }
 
 // Inc by 1
@@ -29,28 +35,100 @@ func (a *AtomicAdder) Dec() {
 	a.Add(-1)
 }
 
+// atomicValue holds an integer of type T as two's complement uint64.
+type atomicValue[T Integer] struct {
+	value uint64
+	overflow
+}
+
+func (a *atomicValue[T]) init(opts []Option) {
+	c := newConfig(opts)
+	a.overflow.init(&c)
+}
+
+// Add the given value
+func (a *atomicValue[T]) Add(x T) {
+	a.add(x, x < 0)
+}
+
+// Inc by 1
+func (a *atomicValue[T]) Inc() {
+	a.add(1, false)
+}
+
+// Dec by 1
+func (a *atomicValue[T]) Dec() {
+	var one T = 1
+	a.add(-one, true)
+}
+
+func (a *atomicValue[T]) add(x T, neg bool) {
+	if a.overflow.enabled {
+		a.addChecked(x, neg)
+		return
+	}
+	atomic.AddUint64(&a.value, toBits(x))
+}
+
+// addChecked adds x unless it would wrap value around, in which case x is spilled.
+// It is kept out of line, so that unchecked Add inlines.
+//
+//go:noinline
+func (a *atomicValue[T]) addChecked(x T, neg bool) {
+	if !addUnlessWrapped(&a.value, x, neg) {
+		spillInto(&a.overflow, x, neg)
+	}
+}
+
 // Sum return the current sum. The returned value is NOT an
 // atomic snapshot because of concurrent update.
-func (a *AtomicAdder) Sum() int64 {
-	return atomic.LoadInt64(&a.value)
+func (a *atomicValue[T]) Sum() T {
+	sum, _ := a.settle(fromBits[T](atomic.LoadUint64(&a.value)), false, a.overflow.enabled)
+	return sum
+}
+
+// String returns the current sum as JSON.
+func (a *atomicValue[T]) String() string {
+	return formatJSON(a.Sum())
+}
+
+// SumChecked return the current sum like Sum, but never wraps around silently. If the sum exceeds
+// range of T, it returns the nearest bound of T, i.e. saturating sum, along with ErrOverflow.
+//
+// The value may wrap around unless the adder is created with WithOverflowCheck,
+// thus an exact result requires that option.
+func (a *atomicValue[T]) SumChecked() (T, error) {
+	return saturate(a.settle(fromBits[T](atomic.LoadUint64(&a.value)), false, true))
 }
 
 // Reset variables maintaining the sum to zero. This method may be a useful alternative
 // to creating a new adder, but is only effective if there are no concurrent updates.
 // Because this method is intrinsically racy.
-func (a *AtomicAdder) Reset() {
-	atomic.StoreInt64(&a.value, 0)
+func (a *atomicValue[T]) Reset() {
+	a.Store(0)
 }
 
-// SumAndReset equivalent in effect to sum followed by reset. Like the nature of Sum and Reset,
-// this function is only effective if there are no concurrent updates.
-func (a *AtomicAdder) SumAndReset() (sum int64) {
-	sum = atomic.LoadInt64(&a.value)
-	atomic.StoreInt64(&a.value, 0)
-	return
+// SumAndReset equivalent in effect to sum followed by reset. The value is atomically swapped to zero,
+// so every update concurrent with this method is counted in exactly one of the returned value or subsequent sums.
+func (a *atomicValue[T]) SumAndReset() T {
+	sum, _ := a.settle(fromBits[T](atomic.SwapUint64(&a.value, 0)), true, a.overflow.enabled)
+	return sum
 }
 
 // Store value. This function is only effective if there are no concurrent updates.
-func (a *AtomicAdder) Store(v int64) {
-	atomic.StoreInt64(&a.value, v)
+func (a *atomicValue[T]) Store(v T) {
+	if a.overflow.enabled {
+		a.overflow.resetSpill()
+	}
+	atomic.StoreUint64(&a.value, toBits(v))
+}
+
+// settle combines v with spilled additions if check is set, see settle.
+func (a *atomicValue[T]) settle(v T, drain, check bool) (T, int) {
+	if !check {
+		return v, 0
+	}
+	var w wide
+	addWide(&w, v)
+	return settle[T](&a.overflow, w, drain)
 }
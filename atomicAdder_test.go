@@ -27,3 +27,7 @@ func TestAtomicAdderNotRaceAdd(t *testing.T) {
 func TestAtomicAdderRaceAdd(t *testing.T) {
 	testAdderRaceAdd(t, AtomicAdderType)
 }
+
+func TestAtomicAdderRaceDrain(t *testing.T) {
+	testAdderRaceDrain(t, AtomicAdderType)
+}
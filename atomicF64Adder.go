@@ -42,6 +42,11 @@ func (a *AtomicF64Adder) Sum() float64 {
 	return math.Float64frombits(atomic.LoadUint64(&a.value))
 }
 
+// String returns the current sum as JSON.
+func (a *AtomicF64Adder) String() string {
+	return formatJSON(a.Sum())
+}
+
 // Reset variables maintaining the sum to zero. This method may be a useful alternative
 // to creating a new adder, but is only effective if there are no concurrent updates.
 // Because this method is intrinsically racy.
@@ -49,12 +54,10 @@ func (a *AtomicF64Adder) Reset() {
 	atomic.StoreUint64(&a.value, 0)
 }
 
-// SumAndReset equivalent in effect to sum followed by reset. Like the nature of Sum and Reset,
-// this function is only effective if there are no concurrent updates.
-func (a *AtomicF64Adder) SumAndReset() (sum float64) {
-	sum = a.Sum()
-	a.Reset()
-	return
+// SumAndReset equivalent in effect to sum followed by reset. The value is atomically swapped to zero,
+// so every update concurrent with this method is counted in exactly one of the returned value or subsequent sums.
+func (a *AtomicF64Adder) SumAndReset() float64 {
+	return math.Float64frombits(atomic.SwapUint64(&a.value, 0))
 }
 
 // Store value. This function is only effective if there are no concurrent updates.
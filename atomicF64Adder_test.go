@@ -27,3 +27,7 @@ func TestAtomicF64AdderNotRaceAdd(t *testing.T) {
 func TestAtomicF64AdderRaceAdd(t *testing.T) {
 	testF64AdderRaceAdd(t, AtomicF64AdderType)
 }
+
+func TestAtomicF64AdderRaceDrain(t *testing.T) {
+	testF64AdderRaceDrain(t, AtomicF64AdderType)
+}
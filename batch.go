@@ -0,0 +1,86 @@
+package goadder
+
+type update[T Number] struct {
+	adder Adder[T]
+	x     T
+}
+
+// Batch groups updates of several adders, which are committed together sharing one probe.
+// A request handler updating many counters could fill a Batch and Commit it once.
+//
+// Updates are not visible to adders until Commit. Repeated updates to the same adder are not
+// merged, each of them is committed as it is. Batch is reusable after Commit.
+//
+// Batch is NOT safe for concurrent use, it is meant to be owned by a single routine.
+type Batch struct {
+	longs  []update[int64]
+	floats []update[float64]
+}
+
+// NewBatch create new Batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Add x to the given long adder upon Commit.
+func (b *Batch) Add(adder LongAdder, x int64) {
+	b.longs = append(b.longs, update[int64]{adder: adder, x: x})
+}
+
+// AddFloat adds x to the given float64 adder upon Commit.
+func (b *Batch) AddFloat(adder Float64Adder, x float64) {
+	b.floats = append(b.floats, update[float64]{adder: adder, x: x})
+}
+
+// Len returns number of pending updates.
+func (b *Batch) Len() int {
+	return len(b.longs) + len(b.floats)
+}
+
+// Commit applies pending updates to their adders, then clears the batch. Striped adders,
+// such as JDKAdder, JDKF64Adder, KahanF64Adder and RandomCellAdder, share one probe.
+// Other adders are updated through Add.
+func (b *Batch) Commit() {
+	probe := getRandomInt()
+
+	// striped adders take the probe from caller, so that the batch pays for a single random probe
+	for _, u := range b.longs {
+		switch a := u.adder.(type) {
+		case *JDKAdder:
+			a.addProbe(u.x, probe)
+		case *AdaptiveAdder:
+			a.addProbe(u.x, probe)
+		case *RandomCellAdder:
+			a.addProbe(u.x, probe)
+		default:
+			a.Add(u.x)
+		}
+	}
+
+	for _, u := range b.floats {
+		switch a := u.adder.(type) {
+		case *JDKF64Adder:
+			a.addProbe(u.x, probe)
+		case *KahanF64Adder:
+			a.addProbe(u.x, probe)
+		default:
+			a.Add(u.x)
+		}
+	}
+
+	b.Discard()
+}
+
+// Discard pending updates.
+func (b *Batch) Discard() {
+	clearUpdates(b.longs)
+	clearUpdates(b.floats)
+	b.longs, b.floats = b.longs[:0], b.floats[:0]
+}
+
+// clearUpdates drops references to adders, so that a reused batch does not retain them.
+func clearUpdates[T Number](updates []update[T]) {
+	for i := range updates {
+		updates[i] = update[T]{}
+	}
+}
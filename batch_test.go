@@ -0,0 +1,69 @@
+package goadder
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBatchRace(t *testing.T) {
+	types := []Type{JDKAdderType, RandomCellAdderType, AtomicAdderType, MutexAdderType, PerPAdderType, AdaptiveAdderType}
+	longs := make([]LongAdder, len(types))
+	for i, ty := range types {
+		longs[i] = NewLongAdder(ty)
+	}
+	floats := []Float64Adder{NewFloat64Adder(JDKF64AdderType), NewFloat64Adder(AtomicF64AdderType), NewFloat64Adder(KahanF64AdderType)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			b := NewBatch()
+			for j := 0; j < delta/100; j++ {
+				for _, adder := range longs {
+					b.Add(adder, 2)
+					b.Add(adder, -1)
+				}
+				for _, adder := range floats {
+					b.AddFloat(adder, 0.5)
+				}
+				b.Commit()
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	n := delta / 100 * numRoutine
+	for i, adder := range longs {
+		if adder.Sum() != int64(n) {
+			t.Errorf("Adder(%d) logic is wrong", types[i])
+		}
+	}
+	for i, adder := range floats {
+		if adder.Sum() != float64(n)/2 {
+			t.Errorf("Float64Adder %d logic is wrong", i)
+		}
+	}
+}
+
+func TestBatchDiscard(t *testing.T) {
+	adder := NewJDKAdder()
+
+	b := NewBatch()
+	b.Add(adder, 1)
+	b.AddFloat(NewJDKF64Adder(), 1)
+	if b.Len() != 2 {
+		t.Errorf("Batch must have 2 pending updates")
+	}
+
+	b.Discard()
+	b.Commit()
+	if b.Len() != 0 || adder.Sum() != 0 {
+		t.Errorf("Discarded updates must not be committed")
+	}
+
+	b.Add(adder, 3)
+	if b.Commit(); b.Len() != 0 || adder.Sum() != 3 {
+		t.Errorf("Batch logic is wrong")
+	}
+}
@@ -0,0 +1,83 @@
+package goadder
+
+import (
+	"math/big"
+)
+
+// BigAdder is an integer adder which never overflows, for lifetime counters which could exceed int64.
+//
+// BigAdder is JDKAdder underneath, so it keeps the same fast path: updates are made to int64 base
+// or Cells upon contention. Only an update which would wrap base or a Cell around takes the slow path,
+// adding into a math/big.Int carry under lock instead. Sum combines base, Cells and carry exactly.
+//
+// BigAdder is high performance and safe for concurrent use.
+type BigAdder struct {
+	adder *JDKAdder
+}
+
+// NewBigAdder create new BigAdder with given options. WithOverflowCheck is ignored since BigAdder never overflows.
+func NewBigAdder(opts ...Option) *BigAdder {
+	opts = append(opts[:len(opts):len(opts)], WithOverflowCheck(nil))
+	return &BigAdder{adder: NewJDKAdderWithOptions(opts...)}
+}
+
+// Add the given value
+func (b *BigAdder) Add(x int64) {
+	b.adder.Add(x)
+}
+
+// AddBig adds the given value. It takes the slow path, so prefer Add for values in range of int64.
+func (b *BigAdder) AddBig(x *big.Int) {
+	o := &b.adder.overflow
+	o.spillLock.Lock()
+	o.spill.Add(&o.spill, x)
+	o.spillLock.Unlock()
+}
+
+// Inc by 1
+func (b *BigAdder) Inc() {
+	b.adder.Inc()
+}
+
+// Dec by 1
+func (b *BigAdder) Dec() {
+	b.adder.Dec()
+}
+
+// Sum return the current sum. The returned value is NOT an
+// atomic snapshot because of concurrent update.
+func (b *BigAdder) Sum() *big.Int {
+	return b.adder.bigSum((*cell[int64]).load, false)
+}
+
+// String returns the current sum in decimal, which is a JSON number.
+func (b *BigAdder) String() string {
+	return b.Sum().String()
+}
+
+// Reset variables maintaining the sum to zero.
+func (b *BigAdder) Reset() {
+	b.SumAndReset()
+}
+
+// SumAndReset equivalent in effect to sum followed by reset. Base, each Cell and carry are
+// atomically swapped to zero, so every update concurrent with this method is counted in exactly
+// one of the returned value or subsequent sums.
+func (b *BigAdder) SumAndReset() *big.Int {
+	return b.adder.bigSum(func(c *cell[int64]) int64 { return c.swap(0) }, true)
+}
+
+// Store value. This function is only effective if there are no concurrent updates.
+func (b *BigAdder) Store(v *big.Int) {
+	b.adder.Store(0)
+
+	o := &b.adder.overflow
+	o.spillLock.Lock()
+	o.spill.Set(v)
+	o.spillLock.Unlock()
+}
+
+// Compact folds all cells into base and shrinks cell table back to its initial size, see JDKAdder.Compact.
+func (b *BigAdder) Compact() {
+	b.adder.Compact()
+}
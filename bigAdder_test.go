@@ -0,0 +1,67 @@
+package goadder
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestBigAdderRace(t *testing.T) {
+	adder := NewBigAdder()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Add(math.MaxInt64)
+				adder.Inc()
+				adder.Dec()
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	expect := new(big.Int).Mul(big.NewInt(math.MaxInt64), big.NewInt(int64(delta/10*numRoutine)))
+	if adder.Sum().Cmp(expect) != 0 || adder.SumAndReset().Cmp(expect) != 0 || adder.Sum().Sign() != 0 {
+		t.Errorf("BigAdder logic is wrong")
+	}
+}
+
+func TestBigAdderNegative(t *testing.T) {
+	adder := NewBigAdder()
+	for i := 0; i < 4; i++ {
+		adder.Add(math.MinInt64)
+	}
+	adder.Dec()
+
+	expect := new(big.Int).Mul(big.NewInt(math.MinInt64), big.NewInt(4))
+	if expect.Sub(expect, big.NewInt(1)); adder.Sum().Cmp(expect) != 0 {
+		t.Errorf("BigAdder logic is wrong: %s", adder.Sum())
+	}
+
+	adder.AddBig(new(big.Int).Neg(expect))
+	if adder.Sum().Sign() != 0 {
+		t.Errorf("BigAdder logic is wrong: %s", adder.Sum())
+	}
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if adder.Store(huge); adder.Sum().Cmp(huge) != 0 {
+		t.Errorf("BigAdder logic is wrong: %s", adder.Sum())
+	}
+}
+
+func TestBigAdderCompact(t *testing.T) {
+	adder := NewBigAdder()
+	adder.Add(math.MaxInt64)
+	adder.adder.accumulate(getRandomInt(), math.MaxInt64, nil, false, true)
+
+	// sweeping the cell into base must not wrap base around
+	adder.Compact()
+	expect := new(big.Int).Mul(big.NewInt(math.MaxInt64), big.NewInt(2))
+	if adder.Sum().Cmp(expect) != 0 {
+		t.Errorf("BigAdder logic is wrong: %s", adder.Sum())
+	}
+}
@@ -0,0 +1,93 @@
+package goadder
+
+import (
+	"sync"
+	"time"
+)
+
+// Compactable is an adder whose cells could be folded back into base. It is implemented
+// by JDKAdder, JDKF64Adder and AdaptiveAdder.
+type Compactable interface {
+	Compact()
+	fingerprint() (uint64, bool)
+}
+
+// Compactor periodically compacts adders whose cells stayed idle for a whole interval,
+// so that memory of cells inflated by a burst of contention could be reclaimed.
+// An adder whose cells are still updated is left untouched.
+//
+// Compactor is safe for concurrent use.
+type Compactor struct {
+	lock     sync.Mutex
+	adders   map[Compactable]uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewCompactor create new Compactor which checks its adders every interval.
+// Stop must be called to release the background routine.
+func NewCompactor(interval time.Duration) *Compactor {
+	c := &Compactor{
+		adders: make(map[Compactable]uint64),
+		stop:   make(chan struct{}),
+	}
+
+	c.wg.Add(1)
+	go c.run(interval)
+
+	return c
+}
+
+// Add adder to be compacted when idle.
+func (c *Compactor) Add(a Compactable) {
+	c.lock.Lock()
+	c.adders[a], _ = a.fingerprint()
+	c.lock.Unlock()
+}
+
+// Remove adder from compactor.
+func (c *Compactor) Remove(a Compactable) {
+	c.lock.Lock()
+	delete(c.adders, a)
+	c.lock.Unlock()
+}
+
+// Stop background routine. Adders are left as they are.
+func (c *Compactor) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	c.wg.Wait()
+}
+
+func (c *Compactor) run(interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.tick()
+		}
+	}
+}
+
+// tick compacts adders whose cells are unchanged since previous tick.
+func (c *Compactor) tick() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for a, last := range c.adders {
+		h, populated := a.fingerprint()
+		if populated && h == last {
+			a.Compact()
+			h, _ = a.fingerprint()
+		}
+		c.adders[a] = h
+	}
+}
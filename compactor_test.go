@@ -0,0 +1,152 @@
+package goadder
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJDKAdderCompact(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithInitialCells(16))
+	adder.Compact() // no table yet
+
+	adder.Add(3)
+	adder.accumulate(getRandomInt(), 4, nil, false, true)
+	if st := adder.Stats(); st.TableLen != 16 || st.PopulatedCells != 1 {
+		t.Errorf("Stats is wrong: %+v", st)
+	}
+
+	adder.Compact()
+	if st := adder.Stats(); st.TableLen != 16 || st.PopulatedCells != 0 || st.Base != 7 {
+		t.Errorf("Compact is wrong: %+v", st)
+	}
+	if adder.Sum() != 7 {
+		t.Errorf("Compact must keep sum")
+	}
+}
+
+func TestJDKAdderRaceCompact(t *testing.T) {
+	adder := NewJDKAdder()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < drainDelta; j++ {
+				adder.Add(int64(j & 7))
+			}
+			wg.Done()
+		}()
+	}
+
+	done := make(chan struct{})
+	drained := make(chan int64)
+	go func() {
+		var total int64
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				drained <- total
+				return
+			default:
+				if adder.Compact(); i%4 == 0 {
+					total += adder.SumAndReset()
+				}
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	expected := int64(drainDelta/8) * 28 * int64(numRoutine)
+	if actual := <-drained + adder.Sum(); actual != expected {
+		t.Errorf("Compact lost updates: %d, expected %d", actual, expected)
+	}
+}
+
+func TestJDKF64AdderRaceCompact(t *testing.T) {
+	adder := NewJDKF64Adder()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < drainDelta; j++ {
+				adder.Add(float64(j & 7))
+			}
+			wg.Done()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				adder.Compact()
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	expected := float64(drainDelta/8) * 28 * float64(numRoutine)
+	if actual := adder.Sum(); actual != expected {
+		t.Errorf("Compact lost updates: %v, expected %v", actual, expected)
+	}
+}
+
+func TestCompactorTick(t *testing.T) {
+	c := NewCompactor(time.Hour)
+	defer c.Stop()
+
+	idle, busy := NewJDKAdder(), NewJDKF64Adder()
+	idle.accumulate(getRandomInt(), 5, nil, false, true)
+	busy.accumulate(getRandomInt(), 5, nil, false, true)
+	c.Add(idle)
+	c.Add(busy)
+
+	busy.accumulate(getRandomInt(), 1, nil, false, true)
+	c.tick()
+	if idle.Stats().PopulatedCells != 0 || idle.Sum() != 5 {
+		t.Errorf("Idle adder must be compacted")
+	}
+	if busy.Stats().PopulatedCells == 0 || busy.Sum() != 6 {
+		t.Errorf("Busy adder must not be compacted")
+	}
+
+	c.tick()
+	if busy.Stats().PopulatedCells != 0 || busy.Sum() != 6 {
+		t.Errorf("Adder idle for an interval must be compacted")
+	}
+
+	c.Remove(busy)
+	busy.accumulate(getRandomInt(), 1, nil, false, true)
+	c.tick()
+	c.tick()
+	if busy.Stats().PopulatedCells == 0 {
+		t.Errorf("Removed adder must not be compacted")
+	}
+}
+
+func TestCompactorRun(t *testing.T) {
+	c := NewCompactor(time.Millisecond)
+
+	adder := NewJDKAdder()
+	adder.accumulate(getRandomInt(), 5, nil, false, true)
+	c.Add(adder)
+
+	for i := 0; i < 1000 && adder.Stats().PopulatedCells != 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	c.Stop()
+	c.Stop()
+
+	if adder.Stats().PopulatedCells != 0 || adder.Sum() != 5 {
+		t.Errorf("Compactor does not run")
+	}
+}
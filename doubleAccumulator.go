@@ -0,0 +1,10 @@
+package goadder
+
+// DoubleAccumulator is ported version of OpenJDK9 DoubleAccumulator, see Accumulator.
+type DoubleAccumulator = Accumulator[float64]
+
+// NewDoubleAccumulator create new DoubleAccumulator with given accumulator function and identity element.
+// The op must not be nil.
+func NewDoubleAccumulator(op FloatBinaryOperator, identity float64) *DoubleAccumulator {
+	return NewAccumulator(op, identity)
+}
@@ -0,0 +1,74 @@
+package goadder
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestDoubleAccumulatorNotRace(t *testing.T) {
+	acc := NewDoubleAccumulator(MinF64Op, math.Inf(1))
+	if !math.IsInf(acc.Get(), 1) {
+		t.Errorf("DoubleAccumulator identity is wrong")
+	}
+
+	for i := 0; i < delta; i++ {
+		acc.Accumulate(float64(i%1000) + 0.5)
+	}
+
+	if acc.Get() != 0.5 || acc.GetThenReset() != 0.5 || !math.IsInf(acc.Get(), 1) {
+		t.Errorf("DoubleAccumulator logic is wrong")
+	}
+
+	acc.Accumulate(12.25)
+	if acc.Get() != 12.25 {
+		t.Errorf("DoubleAccumulator logic is wrong")
+	}
+
+	acc.Reset()
+	if !math.IsInf(acc.Get(), 1) {
+		t.Errorf("DoubleAccumulator logic is wrong")
+	}
+}
+
+func TestDoubleAccumulatorRace(t *testing.T) {
+	acc := NewDoubleAccumulator(MinF64Op, math.Inf(1))
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < delta; j++ {
+				acc.Accumulate(float64(j) - float64(i))
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	tmp := -float64(numRoutine - 1)
+	if acc.Get() != tmp || acc.GetThenReset() != tmp || !math.IsInf(acc.Get(), 1) {
+		t.Errorf("DoubleAccumulator logic is wrong")
+	}
+}
+
+func TestDoubleAccumulatorProduct(t *testing.T) {
+	acc := NewDoubleAccumulator(FloatBinaryFunc(func(l, r float64) float64 { return l * r }), 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 1000; j++ {
+				acc.Accumulate(2)
+				acc.Accumulate(0.5)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if acc.Get() != 1 {
+		t.Errorf("DoubleAccumulator logic is wrong")
+	}
+}
@@ -0,0 +1,163 @@
+package goadder
+
+import (
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTraceIDRunes is the longest trace ID of an exemplar, in runes. OpenMetrics limits exemplar label set
+// to 128 runes, names and values combined, and the trace ID is exposed as label trace_id.
+const MaxTraceIDRunes = 128 - len("trace_id")
+
+// Exemplar is a sampled update of an adder, linking the value added to a trace.
+type Exemplar struct {
+	TraceID   string
+	Value     float64
+	Timestamp time.Time
+}
+
+// ExemplarAdder is an adder recording exemplars of its updates, see AddWithExemplar. It also tracks when it
+// was created or last reset, which OpenMetrics exposes as _created timestamp of counters.
+//
+// Exemplars are kept lock-free in a table of Cells striped like those of Striped64: each update records
+// its exemplar into the Cell of its probe, and the table grows upon contention. Exemplar returns the latest
+// of them. Updates through Add do not record any exemplar.
+//
+// ExemplarAdder is safe for concurrent use.
+type ExemplarAdder[T Number] struct {
+	Adder[T]
+	created   int64
+	exemplars exemplars
+}
+
+// NewExemplarAdder create new ExemplarAdder backed by an adder of numeric type T upon type with given options, see NewAdder.
+func NewExemplarAdder[T Number](t Type, opts ...Option) *ExemplarAdder[T] {
+	a := &ExemplarAdder[T]{Adder: NewAdder[T](t, opts...), created: time.Now().UnixNano()}
+	a.exemplars.conf = newConfig(opts)
+	return a
+}
+
+// AddWithExemplar adds the given value and records it as an exemplar of the given trace. A trace ID longer
+// than MaxTraceIDRunes is not recorded, so that the previous exemplar is kept.
+func (a *ExemplarAdder[T]) AddWithExemplar(x T, traceID string) {
+	a.Add(x)
+	if len(traceID) > MaxTraceIDRunes && utf8.RuneCountInString(traceID) > MaxTraceIDRunes {
+		return
+	}
+	a.exemplars.record(getRandomInt(), &Exemplar{TraceID: traceID, Value: float64(x), Timestamp: time.Now()})
+}
+
+// Exemplar returns the latest exemplar recorded since the adder was created or last reset.
+func (a *ExemplarAdder[T]) Exemplar() (Exemplar, bool) {
+	return a.exemplars.latest(atomic.LoadInt64(&a.created))
+}
+
+// Created returns when the adder was created or last reset, by Reset, SumAndReset or Store.
+func (a *ExemplarAdder[T]) Created() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&a.created))
+}
+
+// String returns the current sum as JSON.
+func (a *ExemplarAdder[T]) String() string {
+	return formatJSON(a.Sum())
+}
+
+// Reset variables maintaining the sum to zero.
+func (a *ExemplarAdder[T]) Reset() {
+	a.Adder.Reset()
+	a.reset()
+}
+
+// SumAndReset is equivalent in effect to Sum followed by Reset.
+func (a *ExemplarAdder[T]) SumAndReset() (sum T) {
+	sum = a.Adder.SumAndReset()
+	a.reset()
+	return
+}
+
+// Store value
+func (a *ExemplarAdder[T]) Store(v T) {
+	a.Adder.Store(v)
+	a.reset()
+}
+
+// reset moves creation time forward, so that exemplars recorded before are dropped.
+func (a *ExemplarAdder[T]) reset() {
+	atomic.StoreInt64(&a.created, time.Now().UnixNano())
+}
+
+// exemplarCell holds the latest *Exemplar recorded into it.
+type exemplarCell struct {
+	_  [7]uint64
+	ex atomic.Value
+	_  [7]uint64
+}
+
+func (c *exemplarCell) record(e *Exemplar) bool {
+	old := c.ex.Load()
+	return c.ex.CompareAndSwap(old, e)
+}
+
+// exemplars is a table of exemplarCells, created and grown upon contention like cells of Striped.
+// Recording into a Cell is a CAS, which only fails if another exemplar was recorded into it meanwhile.
+type exemplars struct {
+	table
+	base exemplarCell
+}
+
+func (s *exemplars) record(probe int, e *Exemplar) {
+	if s.cells.Load() != nil || !s.base.record(e) {
+		s.accumulate(probe, e)
+	}
+}
+
+func (s *exemplars) accumulate(probe int, e *Exemplar) {
+	accumulate[exemplarCell](&s.table, orRandom(probe), exemplarUpdate{s: s, e: e}, true)
+}
+
+// exemplarUpdate records e into base or a cell of exemplars.
+type exemplarUpdate struct {
+	s *exemplars
+	e *Exemplar
+}
+
+func (u exemplarUpdate) tryBase() bool {
+	return u.s.base.record(u.e)
+}
+
+func (u exemplarUpdate) tryCell(c *exemplarCell) bool {
+	return c.record(u.e)
+}
+
+func (u exemplarUpdate) newCell() *exemplarCell {
+	return newExemplarCell(u.e)
+}
+
+func newExemplarCell(e *Exemplar) *exemplarCell {
+	c := &exemplarCell{}
+	c.ex.Store(e)
+	return c
+}
+
+// latest returns the latest exemplar of base and all cells, recorded not before since (unix nanoseconds).
+func (s *exemplars) latest(since int64) (latest Exemplar, ok bool) {
+	consider := func(c *exemplarCell) {
+		if e, _ := c.ex.Load().(*Exemplar); e != nil && e.Timestamp.UnixNano() >= since {
+			if !ok || e.Timestamp.After(latest.Timestamp) {
+				latest, ok = *e, true
+			}
+		}
+	}
+
+	consider(&s.base)
+	if _as := s.cells.Load(); _as != nil {
+		as := _as.(cells)
+		for i := range as {
+			if a := as[i].Load(); a != nil {
+				consider(a.(*exemplarCell))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,85 @@
+package goadder
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExemplarAdder(t *testing.T) {
+	for _, ty := range []Type{JDKAdderType, RandomCellAdderType, AtomicAdderType, PerPAdderType} {
+		adder := NewExemplarAdder[int64](ty, WithMaxCells(4))
+		if _, ok := adder.Exemplar(); ok {
+			t.Errorf("Adder(%d) must have no exemplar", ty)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < numRoutine; i++ {
+			wg.Add(1)
+			go func(i int) {
+				for j := 0; j < delta/100; j++ {
+					if j%10 == 0 {
+						adder.AddWithExemplar(2, strconv.Itoa(i))
+					} else {
+						adder.Inc()
+					}
+				}
+				wg.Done()
+			}(i)
+		}
+		wg.Wait()
+
+		expected := int64(delta/100+(delta/100+9)/10) * int64(numRoutine)
+		if adder.Sum() != expected {
+			t.Errorf("Adder(%d) logic is wrong", ty)
+		}
+
+		e, ok := adder.Exemplar()
+		if !ok || e.Value != 2 || e.TraceID == "" || e.Timestamp.Before(adder.Created()) {
+			t.Errorf("Adder(%d) exemplar is wrong: %+v", ty, e)
+		}
+
+		// the latest exemplar wins, wherever it is recorded
+		time.Sleep(time.Millisecond)
+		adder.AddWithExemplar(7, "last")
+		if e, ok = adder.Exemplar(); !ok || e.TraceID != "last" || e.Value != 7 {
+			t.Errorf("Adder(%d) exemplar is wrong: %+v", ty, e)
+		}
+
+		created := adder.Created()
+		time.Sleep(time.Millisecond)
+		if adder.SumAndReset() != expected+7 || !adder.Created().After(created) {
+			t.Errorf("Adder(%d) must be reset", ty)
+		}
+		if _, ok = adder.Exemplar(); ok {
+			t.Errorf("Adder(%d) must drop exemplars upon reset", ty)
+		}
+	}
+}
+
+func TestExemplarAdderFloat(t *testing.T) {
+	adder := NewExemplarAdder[float64](KahanF64AdderType)
+	adder.AddWithExemplar(0.25, "abc")
+	adder.Add(1)
+
+	if e, ok := adder.Exemplar(); adder.Sum() != 1.25 || !ok || e.Value != 0.25 || e.TraceID != "abc" {
+		t.Errorf("Adder(%d) logic is wrong", KahanF64AdderType)
+	}
+
+	created := adder.Created()
+	time.Sleep(time.Millisecond)
+	if adder.Store(3); adder.Sum() != 3 || !adder.Created().After(created) {
+		t.Errorf("Store(%d) logic is wrong", KahanF64AdderType)
+	}
+}
+
+func TestExemplarAdderLongTraceID(t *testing.T) {
+	adder := NewExemplarAdder[int64](AtomicAdderType)
+	adder.AddWithExemplar(3, strings.Repeat("é", MaxTraceIDRunes))
+	adder.AddWithExemplar(4, strings.Repeat("x", MaxTraceIDRunes+1))
+	if e, ok := adder.Exemplar(); !ok || e.Value != 3 || adder.Sum() != 7 {
+		t.Errorf("Oversized trace ID must not be recorded: %+v", e)
+	}
+}
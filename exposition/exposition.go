@@ -0,0 +1,355 @@
+// Package exposition renders adders in the Prometheus text and OpenMetrics exposition formats, without
+// depending on the Prometheus client library.
+//
+// Adders are added to a Collection as metric families of counter or gauge semantics, with HELP
+// text and label sets. A Collection is rendered by WriteText or WriteOpenMetrics, or served by Handler
+// for a metrics endpoint.
+package exposition
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+var (
+	// ErrInvalidName is returned for a metric or label name not allowed by the exposition format.
+	ErrInvalidName = errors.New("exposition: invalid metric or label name")
+	// ErrConflict is returned when a metric name is already added with another kind or help,
+	// or the same label set is added twice.
+	ErrConflict = errors.New("exposition: conflicting metric")
+)
+
+// Kind of metric family
+type Kind int
+
+const (
+	// Counter is a cumulative metric, which only goes up or is reset to zero
+	Counter Kind = iota
+	// Gauge is a metric which goes up and down
+	Gauge
+)
+
+// String returns name of kind as in TYPE line.
+func (k Kind) String() string {
+	if k == Gauge {
+		return "gauge"
+	}
+	return "counter"
+}
+
+// Labels is a label set, label name to label value.
+type Labels map[string]string
+
+// Collection holds metric families of adders. It is safe for concurrent use.
+type Collection struct {
+	lock       sync.RWMutex
+	families   map[string]*family
+	registries []registry
+	now        func() time.Time
+}
+
+type registry struct {
+	r    *goadder.Registry
+	kind Kind
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    Kind
+	series  map[string]series // by rendered labels
+	sources []func(emit func(labels string, s series))
+}
+
+// series is one adder of a family, exactly one of long and float is set. added is when
+// the series was added to the collection, zero if unknown.
+type series struct {
+	long  goadder.LongAdder
+	float goadder.Float64Adder
+	added time.Time
+}
+
+// createdTracker is implemented by adders tracking when they were created or last reset, such as ExemplarAdder.
+type createdTracker interface {
+	Created() time.Time
+}
+
+// exemplarSampler is implemented by adders recording exemplars, such as ExemplarAdder.
+type exemplarSampler interface {
+	Exemplar() (goadder.Exemplar, bool)
+}
+
+// sample is a series read upon rendering. created is zero if unknown, exemplar is nil if none.
+type sample struct {
+	labels   string
+	long     int64
+	float    float64
+	isFloat  bool
+	created  time.Time
+	exemplar *goadder.Exemplar
+}
+
+// familySnapshot is a family read upon rendering, its samples are in order of labels.
+type familySnapshot struct {
+	name    string
+	help    string
+	kind    Kind
+	samples []sample
+}
+
+// New create new empty Collection.
+func New() *Collection {
+	return &Collection{families: make(map[string]*family), now: time.Now}
+}
+
+// AddLong adds a long adder as a series of metric family name, with given label set.
+//
+// OpenMetrics _created timestamp of a counter is taken from the adder if it tracks it, like ExemplarAdder.
+// Otherwise it is when the adder was added, which is wrong once the adder is reset: prefer ExemplarAdder for
+// counters being reset. Exemplars are exposed for adders recording them, like ExemplarAdder.
+func (c *Collection) AddLong(name, help string, kind Kind, labels Labels, adder goadder.LongAdder) error {
+	return c.add(name, help, kind, labels, series{long: adder})
+}
+
+// AddFloat adds a float64 adder as a series of metric family name, with given label set.
+// See AddLong about _created timestamps and exemplars.
+func (c *Collection) AddFloat(name, help string, kind Kind, labels Labels, adder goadder.Float64Adder) error {
+	return c.add(name, help, kind, labels, series{float: adder})
+}
+
+// AddMap adds all adders of an AdderMap as series of metric family name. Each key is rendered
+// by fmt.Sprint as value of label key, next to the given constant labels. Keys created later
+// are rendered as well. Their _created timestamps are unknown, thus not exposed.
+func AddMap[K comparable](c *Collection, name, help string, kind Kind, labels Labels, key string, m *goadder.AdderMap[K]) error {
+	if !validLabelName(key) || labels[key] != "" {
+		return ErrInvalidName
+	}
+	if err := validLabels(labels); err != nil {
+		return err
+	}
+
+	source := func(emit func(labels string, s series)) {
+		l := make(Labels, len(labels)+1)
+		for k, v := range labels {
+			l[k] = v
+		}
+
+		m.Range(func(k K, adder goadder.LongAdder) bool {
+			l[key] = fmt.Sprint(k)
+			emit(formatLabels(l), series{long: adder})
+			return true
+		})
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	f, err := c.family(name, help, kind)
+	if err == nil {
+		f.sources = append(f.sources, source)
+	}
+	return err
+}
+
+// AddRegistry adds all adders of a registry with given kind, each adder being a metric family
+// without label. Adders registered later are rendered as well. Characters of registry names not
+// allowed by the exposition format are replaced by underscore. Adders whose name is taken by
+// a family of the collection, or by an adder of this or a previous registry once sanitized, are
+// skipped. Their _created timestamps are unknown, thus not exposed.
+func (c *Collection) AddRegistry(r *goadder.Registry, kind Kind) {
+	c.lock.Lock()
+	c.registries = append(c.registries, registry{r: r, kind: kind})
+	c.lock.Unlock()
+}
+
+func (c *Collection) add(name, help string, kind Kind, labels Labels, s series) error {
+	if err := validLabels(labels); err != nil {
+		return err
+	}
+	rendered := formatLabels(labels)
+	s.added = c.now()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	f, err := c.family(name, help, kind)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.series[rendered]; ok {
+		return ErrConflict
+	}
+	f.series[rendered] = s
+	return nil
+}
+
+// family returns family by name, creating it if needed. Caller must hold the lock.
+func (c *Collection) family(name, help string, kind Kind) (*family, error) {
+	if !validMetricName(name) {
+		return nil, ErrInvalidName
+	}
+
+	f, ok := c.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind, series: make(map[string]series)}
+		c.families[name] = f
+	} else if f.kind != kind || f.help != help {
+		return nil, ErrConflict
+	}
+	return f, nil
+}
+
+// snapshot reads all adders, families are in order of name.
+func (c *Collection) snapshot() []familySnapshot {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	families := make([]familySnapshot, 0, len(c.families))
+	for _, f := range c.families {
+		fs := familySnapshot{name: f.name, help: f.help, kind: f.kind}
+		for labels, s := range f.series {
+			fs.samples = append(fs.samples, s.read(labels))
+		}
+		for _, source := range f.sources {
+			source(func(labels string, s series) {
+				fs.samples = append(fs.samples, s.read(labels))
+			})
+		}
+		sort.Slice(fs.samples, func(i, j int) bool { return fs.samples[i].labels < fs.samples[j].labels })
+		families = append(families, fs)
+	}
+
+	emitted := make(map[string]struct{})
+	for _, reg := range c.registries {
+		reg.r.Each(func(e goadder.Entry) bool {
+			name := sanitizeName(e.Name)
+			if _, ok := c.families[name]; ok {
+				return true
+			}
+			if _, ok := emitted[name]; ok {
+				return true
+			}
+			emitted[name] = struct{}{}
+			s := series{long: e.Long, float: e.Float}
+			families = append(families, familySnapshot{name: name, kind: reg.kind, samples: []sample{s.read("")}})
+			return true
+		})
+	}
+
+	sort.SliceStable(families, func(i, j int) bool { return families[i].name < families[j].name })
+	return families
+}
+
+func (s series) read(labels string) (r sample) {
+	var adder interface{}
+	if s.float != nil {
+		r = sample{labels: labels, float: s.float.Sum(), isFloat: true}
+		adder = s.float
+	} else {
+		r = sample{labels: labels, long: s.long.Sum()}
+		adder = s.long
+	}
+
+	r.created = s.added
+	if a, ok := adder.(createdTracker); ok {
+		r.created = a.Created()
+	}
+	if a, ok := adder.(exemplarSampler); ok {
+		if e, ok := a.Exemplar(); ok {
+			r.exemplar = &e
+		}
+	}
+	return
+}
+
+func validLabels(labels Labels) error {
+	for name := range labels {
+		if !validLabelName(name) {
+			return ErrInvalidName
+		}
+	}
+	return nil
+}
+
+// validMetricName reports whether name matches [a-zA-Z_:][a-zA-Z0-9_:]*
+func validMetricName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !isNameRune(r, i > 0) && r != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// validLabelName reports whether name matches [a-zA-Z_][a-zA-Z0-9_]* and is not reserved,
+// that is starting with __.
+func validLabelName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "__") {
+		return false
+	}
+	for i, r := range name {
+		if !isNameRune(r, i > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameRune(r rune, digitAllowed bool) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (digitAllowed && r >= '0' && r <= '9')
+}
+
+// sanitizeName replaces characters not allowed in a metric name by underscore.
+func sanitizeName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	b := []byte(name)
+	for i, r := range b {
+		if !isNameRune(rune(r), i > 0) && r != ':' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// formatLabels renders labels in order of name, e.g. {code="200",method="get"}. Empty set is rendered as empty string.
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		labelValueEscaper.WriteString(&b, labels[name])
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
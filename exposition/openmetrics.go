@@ -0,0 +1,94 @@
+package exposition
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+// OpenMetricsContentType is content type of the OpenMetrics text format.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// WriteOpenMetrics renders collection in the OpenMetrics text format, families in order of name
+// and series of a family in order of labels.
+//
+// Counter samples are suffixed by _total, a trailing _total of the family name is dropped. Each is followed
+// by its _created timestamp when known, see AddLong, and carries its latest exemplar if any. Exemplars whose
+// trace ID exceeds the length limit of OpenMetrics are omitted.
+func (c *Collection) WriteOpenMetrics(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range c.snapshot() {
+		name := f.name
+		if f.kind == Counter {
+			name = strings.TrimSuffix(name, "_total")
+		}
+
+		bw.WriteString("# TYPE ")
+		bw.WriteString(name)
+		bw.WriteByte(' ')
+		bw.WriteString(f.kind.String())
+		bw.WriteByte('\n')
+
+		if f.help != "" {
+			bw.WriteString("# HELP ")
+			bw.WriteString(name)
+			bw.WriteByte(' ')
+			labelValueEscaper.WriteString(bw, f.help)
+			bw.WriteByte('\n')
+		}
+
+		for _, s := range f.samples {
+			if f.kind == Gauge {
+				bw.WriteString(name)
+				bw.WriteString(s.labels)
+				bw.WriteByte(' ')
+				bw.Write(s.appendValue(bw.AvailableBuffer()))
+				bw.WriteByte('\n')
+				continue
+			}
+
+			bw.WriteString(name)
+			bw.WriteString("_total")
+			bw.WriteString(s.labels)
+			bw.WriteByte(' ')
+			bw.Write(s.appendValue(bw.AvailableBuffer()))
+			if e := s.exemplar; e != nil && utf8.RuneCountInString(e.TraceID) <= goadder.MaxTraceIDRunes {
+				bw.WriteString(` # {trace_id="`)
+				labelValueEscaper.WriteString(bw, e.TraceID)
+				bw.WriteString(`"} `)
+				bw.Write(appendFloat(bw.AvailableBuffer(), e.Value))
+				bw.WriteByte(' ')
+				bw.Write(appendTimestamp(bw.AvailableBuffer(), e.Timestamp))
+			}
+			bw.WriteByte('\n')
+
+			if !s.created.IsZero() {
+				bw.WriteString(name)
+				bw.WriteString("_created")
+				bw.WriteString(s.labels)
+				bw.WriteByte(' ')
+				bw.Write(appendTimestamp(bw.AvailableBuffer(), s.created))
+				bw.WriteByte('\n')
+			}
+		}
+	}
+
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// appendTimestamp formats t as seconds since epoch, with nanoseconds as fraction without trailing zeros.
+func appendTimestamp(b []byte, t time.Time) []byte {
+	b = strconv.AppendInt(b, t.Unix(), 10)
+	if ns := t.Nanosecond(); ns != 0 {
+		frac := strconv.AppendInt(nil, int64(ns+1e9), 10) // leading 1 keeps zeros of padding
+		b = append(b, '.')
+		b = append(b, strings.TrimRight(string(frac[1:]), "0")...)
+	}
+	return b
+}
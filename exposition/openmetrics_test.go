@@ -0,0 +1,86 @@
+package exposition
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testCollection(t).WriteOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	testGolden(t, "openmetrics.txt", buf.Bytes())
+
+	// empty collection
+	buf.Reset()
+	if err := New().WriteOpenMetrics(&buf); err != nil || buf.String() != "# EOF\n" {
+		t.Errorf("Empty collection must render EOF only")
+	}
+}
+
+func TestHandlerOpenMetrics(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0,text/plain;version=0.0.4;q=0.5")
+
+	rec := httptest.NewRecorder()
+	testCollection(t).Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != OpenMetricsContentType {
+		t.Errorf("Handler logic is wrong: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	testGolden(t, "openmetrics.txt", rec.Body.Bytes())
+}
+
+func TestOpenMetricsExemplarAdder(t *testing.T) {
+	adder := goadder.NewExemplarAdder[int64](goadder.JDKAdderType)
+	adder.AddWithExemplar(3, "abc")
+	adder.AddWithExemplar(4, strings.Repeat("x", goadder.MaxTraceIDRunes+1)) // not recorded
+
+	c := New()
+	if err := c.AddLong("requests", "", Counter, nil, adder); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[1], `requests_total 7 # {trace_id="abc"} 3 `) || lines[2] != "requests_created "+string(appendTimestamp(nil, adder.Created())) {
+		t.Errorf("OpenMetrics logic is wrong:\n%s", buf.String())
+	}
+
+	// reset moves _created and drops exemplars
+	time.Sleep(time.Millisecond)
+	adder.SumAndReset()
+	adder.AddWithExemplar(5, "def")
+
+	buf.Reset()
+	c.WriteOpenMetrics(&buf)
+	if lines = strings.Split(buf.String(), "\n"); !strings.HasPrefix(lines[1], `requests_total 5 # {trace_id="def"} 5 `) ||
+		lines[2] != "requests_created "+string(appendTimestamp(nil, adder.Created())) {
+		t.Errorf("OpenMetrics logic is wrong:\n%s", buf.String())
+	}
+}
+
+func TestAppendTimestamp(t *testing.T) {
+	for _, c := range []struct {
+		t        time.Time
+		expected string
+	}{
+		{time.Unix(1700000000, 0), "1700000000"},
+		{time.Unix(1700000000, 5), "1700000000.000000005"},
+		{time.Unix(1700000000, 120000000), "1700000000.12"},
+	} {
+		if actual := string(appendTimestamp(nil, c.t)); actual != c.expected {
+			t.Errorf("appendTimestamp(%v) = %s, expected %s", c.t, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package exposition
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TextContentType is content type of the Prometheus text exposition format.
+const TextContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteText renders collection in the Prometheus text exposition format. Families are in order of name,
+// series of a family in order of labels.
+func (c *Collection) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range c.snapshot() {
+		if f.help != "" {
+			bw.WriteString("# HELP ")
+			bw.WriteString(f.name)
+			bw.WriteByte(' ')
+			helpEscaper.WriteString(bw, f.help)
+			bw.WriteByte('\n')
+		}
+
+		bw.WriteString("# TYPE ")
+		bw.WriteString(f.name)
+		bw.WriteByte(' ')
+		bw.WriteString(f.kind.String())
+		bw.WriteByte('\n')
+
+		for _, s := range f.samples {
+			bw.WriteString(f.name)
+			bw.WriteString(s.labels)
+			bw.WriteByte(' ')
+			bw.Write(s.appendValue(bw.AvailableBuffer()))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler returns an http.Handler serving collection in the OpenMetrics text format if the request
+// accepts it, and in the Prometheus text exposition format otherwise.
+func (c *Collection) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write, contentType := c.WriteText, TextContentType
+		if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+			write, contentType = c.WriteOpenMetrics, OpenMetricsContentType
+		}
+
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+func (s sample) appendValue(b []byte) []byte {
+	if s.isFloat {
+		return appendFloat(b, s.float)
+	}
+	return strconv.AppendInt(b, s.long, 10)
+}
+
+// appendFloat formats x as the shortest representation that reads back exactly, with
+// +Inf, -Inf and NaN spelled as the exposition format expects.
+func appendFloat(b []byte, x float64) []byte {
+	switch {
+	case math.IsInf(x, 1):
+		return append(b, "+Inf"...)
+	case math.IsInf(x, -1):
+		return append(b, "-Inf"...)
+	case math.IsNaN(x):
+		return append(b, "NaN"...)
+	default:
+		return strconv.AppendFloat(b, x, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,174 @@
+package exposition
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// tracedAdder is a long adder with fixed creation time and exemplar.
+type tracedAdder struct {
+	goadder.LongAdder
+	created  time.Time
+	exemplar goadder.Exemplar
+}
+
+func (a *tracedAdder) Created() time.Time {
+	return a.created
+}
+
+func (a *tracedAdder) Exemplar() (goadder.Exemplar, bool) {
+	return a.exemplar, a.exemplar.TraceID != ""
+}
+
+func testCollection(t *testing.T) *Collection {
+	c := New()
+	c.now = func() time.Time { return time.Unix(1700000000, 120000000) }
+
+	requests := goadder.NewLongAdder(goadder.JDKAdderType)
+	requests.Add(1027)
+	errs := goadder.NewLongAdder(goadder.AtomicAdderType)
+	errs.Add(3)
+	if err := c.AddLong("http_requests_total", "Total HTTP requests.", Counter, Labels{"method": "get", "code": "200"}, requests); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddLong("http_requests_total", "Total HTTP requests.", Counter, Labels{"method": "post", "code": "500"}, errs); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := &tracedAdder{
+		LongAdder: goadder.NewLongAdder(goadder.PerPAdderType),
+		created:   time.Unix(1700000100, 0),
+		exemplar:  goadder.Exemplar{TraceID: "4bf92f3577b34da6", Value: 2, Timestamp: time.Unix(1700000200, 5)},
+	}
+	jobs.Add(9)
+	if err := c.AddLong("jobs_total", "Jobs done.", Counter, Labels{"queue": "mail"}, jobs); err != nil {
+		t.Fatal(err)
+	}
+	untraced := &tracedAdder{LongAdder: goadder.NewLongAdder(goadder.AtomicAdderType), created: time.Unix(1700000300, 0)}
+	if err := c.AddLong("jobs_total", "Jobs done.", Counter, Labels{"queue": "sms"}, untraced); err != nil {
+		t.Fatal(err)
+	}
+
+	inflight := goadder.NewLongAdder(goadder.RandomCellAdderType)
+	inflight.Add(-2)
+	if err := c.AddLong("inflight", "Requests in flight,\nmaybe negative \\ transiently.", Gauge, nil, inflight); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, x := range []float64{0.1, 1e21, math.Inf(1), math.Inf(-1), math.NaN(), -0.000123} {
+		temp := goadder.NewFloat64Adder(goadder.KahanF64AdderType)
+		temp.Add(x)
+		if err := c.AddFloat("temperature", "", Gauge, Labels{"sensor": string(rune('a' + i)), "path": "C:\\dir\n\"quoted\""}, temp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := goadder.NewAdderMap[int](goadder.JDKAdderType, 2, -1)
+	for _, k := range []int{404, 200, 200, 503, 302} {
+		m.Inc(k)
+	}
+	if err := AddMap(c, "responses_total", "Responses by code.", Counter, Labels{"service": "api"}, "code", m); err != nil {
+		t.Fatal(err)
+	}
+
+	r := goadder.NewRegistry()
+	queued, _ := r.GetOrCreateLongAdder("queue.jobs-done", goadder.PerPAdderType)
+	queued.Add(42)
+	latency, _ := r.GetOrCreateFloat64Adder("latency_seconds", goadder.JDKF64AdderType)
+	latency.Add(1.5)
+	r.GetOrCreateLongAdder("inflight", goadder.AtomicAdderType) // taken by the collection, skipped
+	dup, _ := r.GetOrCreateLongAdder("queue_jobs_done", goadder.AtomicAdderType)
+	dup.Add(7) // taken by queue.jobs-done once sanitized, skipped
+	c.AddRegistry(r, Counter)
+
+	other := goadder.NewRegistry()
+	shadowed, _ := other.GetOrCreateFloat64Adder("latency_seconds", goadder.AtomicF64AdderType)
+	shadowed.Add(9) // taken by the previous registry, skipped
+	c.AddRegistry(other, Counter)
+
+	return c
+}
+
+func testGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("%s mismatch, got:\n%s\nexpected:\n%s", name, actual, expected)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testCollection(t).WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	testGolden(t, "text.prom", buf.Bytes())
+
+	// empty collection
+	buf.Reset()
+	if err := New().WriteText(&buf); err != nil || buf.Len() != 0 {
+		t.Errorf("Empty collection must render nothing")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	testCollection(t).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != TextContentType {
+		t.Errorf("Handler logic is wrong: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	testGolden(t, "text.prom", rec.Body.Bytes())
+}
+
+func TestCollectionErrors(t *testing.T) {
+	c := New()
+	adder := goadder.NewLongAdder(goadder.JDKAdderType)
+
+	for _, name := range []string{"", "1abc", "a-b", "a b"} {
+		if err := c.AddLong(name, "", Counter, nil, adder); err != ErrInvalidName {
+			t.Errorf("Metric name %q must be invalid", name)
+		}
+	}
+	for _, name := range []string{"", "__name__", "a:b", "0a"} {
+		if err := c.AddLong("ok", "", Counter, Labels{name: "v"}, adder); err != ErrInvalidName {
+			t.Errorf("Label name %q must be invalid", name)
+		}
+	}
+	if err := AddMap(c, "ok", "", Counter, Labels{"key": "v"}, "key", goadder.NewAdderMap[string](goadder.JDKAdderType, 0, "")); err != ErrInvalidName {
+		t.Errorf("Map key label must not collide with constant labels")
+	}
+
+	if err := c.AddLong("ns:ok_total", "help", Counter, Labels{"a": "1"}, adder); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddLong("ns:ok_total", "help", Counter, Labels{"a": "1"}, adder); err != ErrConflict {
+		t.Errorf("Duplicated label set must conflict")
+	}
+	if err := c.AddLong("ns:ok_total", "help", Gauge, Labels{"a": "2"}, adder); err != ErrConflict {
+		t.Errorf("Kind must conflict")
+	}
+	if err := c.AddLong("ns:ok_total", "other help", Counter, Labels{"a": "2"}, adder); err != ErrConflict {
+		t.Errorf("Help must conflict")
+	}
+}
@@ -0,0 +1,44 @@
+// Package expvars publishes adders as expvar variables, served as JSON on /debug/vars.
+//
+// Every adder of goadder is an expvar.Var already, through its String method. This package only holds
+// helpers creating and publishing adders at once. It is apart from goadder since importing expvar
+// registers /debug/vars on http.DefaultServeMux, which not every user of adders wants.
+package expvars
+
+import (
+	"expvar"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+// Var adapts adder to expvar.Var, for adders without String method such as user implementations of goadder.Adder.
+func Var[T goadder.Number](adder goadder.Adder[T]) expvar.Var {
+	if v, ok := adder.(expvar.Var); ok {
+		return v
+	}
+	return expvar.Func(func() interface{} { return adder.Sum() })
+}
+
+// PublishLongAdder create new long adder upon type with given options, see goadder.NewLongAdder,
+// and publishes it by name. Like expvar.Publish, it panics if name is already published.
+func PublishLongAdder(name string, t goadder.Type, opts ...goadder.Option) goadder.LongAdder {
+	adder := goadder.NewLongAdder(t, opts...)
+	expvar.Publish(name, Var(adder))
+	return adder
+}
+
+// PublishFloat64Adder create new float64 adder upon type with given options, see goadder.NewFloat64Adder,
+// and publishes it by name. Like expvar.Publish, it panics if name is already published.
+func PublishFloat64Adder(name string, t goadder.Type, opts ...goadder.Option) goadder.Float64Adder {
+	adder := goadder.NewFloat64Adder(t, opts...)
+	expvar.Publish(name, Var(adder))
+	return adder
+}
+
+// PublishAdderMap create new AdderMap, see goadder.NewAdderMap, and publishes it by name as JSON object
+// of sum by key. Like expvar.Publish, it panics if name is already published.
+func PublishAdderMap[K comparable](name string, t goadder.Type, maxKeys int, overflowKey K, opts ...goadder.Option) *goadder.AdderMap[K] {
+	m := goadder.NewAdderMap(t, maxKeys, overflowKey, opts...)
+	expvar.Publish(name, m)
+	return m
+}
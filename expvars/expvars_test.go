@@ -0,0 +1,56 @@
+package expvars
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+// plainAdder is a user implementation of LongAdder without String method.
+type plainAdder struct {
+	goadder.LongAdder
+}
+
+func TestPublish(t *testing.T) {
+	long := PublishLongAdder("test.requests", goadder.PerPAdderType)
+	long.Add(12)
+	float := PublishFloat64Adder("test.latency", goadder.KahanF64AdderType)
+	float.Add(0.5)
+	m := PublishAdderMap("test.status", goadder.JDKAdderType, 1, 0)
+	m.Add(200, 3)
+	m.Add(503, 1)
+	expvar.Publish("test.plain", Var[int64](plainAdder{goadder.NewLongAdder(goadder.AtomicAdderType)}))
+
+	for name, expected := range map[string]string{
+		"test.requests": "12",
+		"test.latency":  "0.5",
+		"test.status":   `{"0": 1, "200": 3}`,
+		"test.plain":    "0",
+	} {
+		if actual := expvar.Get(name).String(); actual != expected {
+			t.Errorf("%s: %s, expected %s", name, actual, expected)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	expvar.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+	var vars map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if vars["test.requests"] != 12.0 || vars["test.status"].(map[string]interface{})["200"] != 3.0 {
+		t.Errorf("/debug/vars is wrong: %v", vars)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Publishing a name twice must panic")
+		}
+	}()
+	PublishLongAdder("test.requests", goadder.JDKAdderType)
+}
@@ -0,0 +1,241 @@
+package goadder
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// MaxScale is the maximum scale of FixedPointAdder.
+const MaxScale = 18
+
+var (
+	// ErrInvalidDecimal is returned when a value could not be parsed as a decimal number.
+	ErrInvalidDecimal = errors.New("goadder: invalid decimal")
+	// ErrInexact is returned when a value needs rounding but RoundUnnecessary is given.
+	ErrInexact = errors.New("goadder: rounding necessary")
+)
+
+// RoundingMode tells how to round a value having more fractional digits than scale of FixedPointAdder.
+type RoundingMode int
+
+const (
+	// RoundUnnecessary rejects values which need rounding with ErrInexact.
+	RoundUnnecessary RoundingMode = iota
+	// RoundHalfEven rounds to nearest, ties to even. Also known as banker's rounding.
+	RoundHalfEven
+	// RoundHalfUp rounds to nearest, ties away from zero.
+	RoundHalfUp
+	// RoundDown rounds toward zero, i.e. truncates.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+)
+
+// FixedPointAdder is a decimal adder for money and other quantities which must be exact. Values are
+// kept as integer number of minor units, 10^-scale each, in a JDKAdder with overflow check.
+// So FixedPointAdder has the same performance characteristics as JDKAdder.
+//
+// Values having more fractional digits than scale are rounded by given RoundingMode. Sum is exact
+// and formatted as decimal string, even if it exceeds range of int64 minor units. SumUnits reports
+// ErrOverflow in that case.
+type FixedPointAdder struct {
+	units *JDKAdder
+	scale int
+}
+
+// NewFixedPointAdder create new FixedPointAdder with given scale, i.e. number of fractional digits.
+// For example, scale 4 keeps values in units of 10^-4. Options apply to the underlying JDKAdder,
+// overflow check is always enabled. It panics if scale is negative or greater than MaxScale.
+func NewFixedPointAdder(scale int, opts ...Option) *FixedPointAdder {
+	if scale < 0 || scale > MaxScale {
+		panic("goadder: scale of FixedPointAdder out of range")
+	}
+
+	if c := newConfig(opts); !c.overflowCheck {
+		opts = append(opts[:len(opts):len(opts)], WithOverflowCheck(nil))
+	}
+
+	return &FixedPointAdder{units: NewJDKAdderWithOptions(opts...), scale: scale}
+}
+
+// Scale returns number of fractional digits.
+func (f *FixedPointAdder) Scale() int {
+	return f.scale
+}
+
+// AddUnits adds the given number of minor units, i.e. x * 10^-scale.
+func (f *FixedPointAdder) AddUnits(x int64) {
+	f.units.Add(x)
+}
+
+// AddString adds the given decimal, such as "-12.3456", rounded by mode. Exponents are not accepted.
+// It returns ErrInvalidDecimal, ErrInexact or ErrOverflow if x could not be converted to minor units
+// of int64, in which case nothing is added.
+func (f *FixedPointAdder) AddString(x string, mode RoundingMode) error {
+	v, err := parseUnits(x, f.scale, mode)
+	if err == nil {
+		f.units.Add(v)
+	}
+	return err
+}
+
+// AddFloat adds the given float, rounded by mode. The float is taken as the shortest decimal
+// representing it, so 0.1 is added as 0.1 rather than 0.1000000000000000055511151231257827.
+// It returns ErrInvalidDecimal for NaN and infinities, see AddString for other errors.
+func (f *FixedPointAdder) AddFloat(x float64, mode RoundingMode) error {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return ErrInvalidDecimal
+	}
+	return f.AddString(strconv.FormatFloat(x, 'f', -1, 64), mode)
+}
+
+// Sum return the current sum as decimal string with exactly scale fractional digits.
+// The returned value is NOT an atomic snapshot because of concurrent update.
+func (f *FixedPointAdder) Sum() string {
+	return formatUnits(f.units.bigSum((*cell[int64]).load, false), f.scale)
+}
+
+// String returns the current sum as JSON number, exact like Sum.
+func (f *FixedPointAdder) String() string {
+	return f.Sum()
+}
+
+// SumUnits return the current sum in minor units. If the sum exceeds range of int64,
+// it returns the nearest bound of int64 along with ErrOverflow.
+func (f *FixedPointAdder) SumUnits() (int64, error) {
+	return f.units.SumChecked()
+}
+
+// SumAndReset equivalent in effect to sum followed by reset, see JDKAdder.SumAndReset.
+func (f *FixedPointAdder) SumAndReset() string {
+	return formatUnits(f.units.bigSum(func(c *cell[int64]) int64 { return c.swap(0) }, true), f.scale)
+}
+
+// Reset variables maintaining the sum to zero.
+func (f *FixedPointAdder) Reset() {
+	f.SumAndReset()
+}
+
+// StoreUnits stores the given number of minor units. This function is only effective if there are no concurrent updates.
+func (f *FixedPointAdder) StoreUnits(v int64) {
+	f.units.Store(v)
+}
+
+// Overflowed reports whether the sum was found exceeding range of int64 minor units, see WithOverflowCheck.
+func (f *FixedPointAdder) Overflowed() bool {
+	return f.units.Overflowed()
+}
+
+// parseUnits converts decimal s to minor units of given scale, rounded by mode.
+func parseUnits(s string, scale int, mode RoundingMode) (int64, error) {
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, ErrInvalidDecimal
+	}
+
+	// split fraction into kept digits and dropped ones
+	dropped := ""
+	if len(fracPart) > scale {
+		fracPart, dropped = fracPart[:scale], fracPart[scale:]
+	}
+	digits := intPart + fracPart + strings.Repeat("0", scale-len(fracPart))
+
+	var mag uint64
+	for i := 0; i < len(digits); i++ {
+		hi, lo := bits.Mul64(mag, 10)
+		lo, carry := bits.Add64(lo, uint64(digits[i]-'0'), 0)
+		if hi != 0 || carry != 0 {
+			return 0, ErrOverflow
+		}
+		mag = lo
+	}
+
+	if roundsAway(mag, dropped, neg, mode) {
+		if mag++; mag == 0 {
+			return 0, ErrOverflow
+		}
+	} else if mode == RoundUnnecessary && strings.Trim(dropped, "0") != "" {
+		return 0, ErrInexact
+	}
+
+	if neg {
+		if mag > 1<<63 {
+			return 0, ErrOverflow
+		}
+		return -int64(mag), nil
+	}
+	if mag > math.MaxInt64 {
+		return 0, ErrOverflow
+	}
+	return int64(mag), nil
+}
+
+// roundsAway reports whether magnitude mag must be incremented after dropping digits.
+func roundsAway(mag uint64, dropped string, neg bool, mode RoundingMode) bool {
+	if strings.Trim(dropped, "0") == "" { // exact
+		return false
+	}
+
+	switch mode {
+	case RoundUp:
+		return true
+	case RoundFloor:
+		return neg
+	case RoundCeiling:
+		return !neg
+	case RoundHalfUp:
+		return dropped[0] >= '5'
+	case RoundHalfEven:
+		if dropped[0] != '5' {
+			return dropped[0] > '5'
+		}
+		return strings.Trim(dropped[1:], "0") != "" || mag&1 == 1
+	default: // RoundDown, RoundUnnecessary
+		return false
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatUnits formats v minor units of given scale as decimal string.
+func formatUnits(v *big.Int, scale int) string {
+	digits := new(big.Int).Abs(v).String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	var sb strings.Builder
+	if v.Sign() < 0 {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(digits[:len(digits)-scale])
+	if scale > 0 {
+		sb.WriteByte('.')
+		sb.WriteString(digits[len(digits)-scale:])
+	}
+	return sb.String()
+}
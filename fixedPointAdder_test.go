@@ -0,0 +1,147 @@
+package goadder
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestFixedPointAdderRace(t *testing.T) {
+	adder := NewFixedPointAdder(4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				_ = adder.AddFloat(0.1, RoundUnnecessary)
+				_ = adder.AddString("-0.0999", RoundUnnecessary)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	units := int64(delta/10) * int64(numRoutine)
+	if v, err := adder.SumUnits(); err != nil || v != units {
+		t.Errorf("FixedPointAdder logic is wrong")
+	}
+	if adder.SumAndReset() != formatUnits(bigOf(units), 4) || adder.Sum() != "0.0000" {
+		t.Errorf("FixedPointAdder logic is wrong")
+	}
+}
+
+func TestFixedPointAdderRounding(t *testing.T) {
+	cases := []struct {
+		in   string
+		mode RoundingMode
+		out  int64
+		err  error
+	}{
+		{"1.23", RoundUnnecessary, 123, nil},
+		{"+1.2", RoundUnnecessary, 120, nil},
+		{"-.5", RoundUnnecessary, -50, nil},
+		{"7.", RoundUnnecessary, 700, nil},
+		{"1.2300", RoundUnnecessary, 123, nil},
+		{"1.235", RoundUnnecessary, 0, ErrInexact},
+		{"1.235", RoundHalfEven, 124, nil},
+		{"1.245", RoundHalfEven, 124, nil},
+		{"1.2451", RoundHalfEven, 125, nil},
+		{"-1.245", RoundHalfEven, -124, nil},
+		{"1.245", RoundHalfUp, 125, nil},
+		{"-1.245", RoundHalfUp, -125, nil},
+		{"1.2449", RoundHalfUp, 124, nil},
+		{"1.239", RoundDown, 123, nil},
+		{"-1.239", RoundDown, -123, nil},
+		{"1.231", RoundUp, 124, nil},
+		{"-1.231", RoundUp, -124, nil},
+		{"-1.231", RoundFloor, -124, nil},
+		{"1.231", RoundFloor, 123, nil},
+		{"-1.239", RoundCeiling, -123, nil},
+		{"1.231", RoundCeiling, 124, nil},
+		{"92233720368547758.07", RoundUnnecessary, math.MaxInt64, nil},
+		{"-92233720368547758.08", RoundUnnecessary, math.MinInt64, nil},
+		{"92233720368547758.08", RoundUnnecessary, 0, ErrOverflow},
+		{"92233720368547758.071", RoundUp, 0, ErrOverflow},
+		{"1e5", RoundUnnecessary, 0, ErrInvalidDecimal},
+		{"", RoundUnnecessary, 0, ErrInvalidDecimal},
+		{"-", RoundUnnecessary, 0, ErrInvalidDecimal},
+		{".", RoundUnnecessary, 0, ErrInvalidDecimal},
+		{"1.2.3", RoundUnnecessary, 0, ErrInvalidDecimal},
+	}
+
+	for _, c := range cases {
+		if v, err := parseUnits(c.in, 2, c.mode); v != c.out || err != c.err {
+			t.Errorf("parseUnits(%q, %d) = %d, %v", c.in, c.mode, v, err)
+		}
+	}
+}
+
+func TestFixedPointAdderFloat(t *testing.T) {
+	adder := NewFixedPointAdder(2)
+	for i := 0; i < 10; i++ {
+		if err := adder.AddFloat(0.1, RoundUnnecessary); err != nil {
+			t.Errorf("AddFloat logic is wrong: %v", err)
+		}
+	}
+	if err := adder.AddFloat(0.005, RoundHalfEven); err != nil || adder.Sum() != "1.00" {
+		t.Errorf("AddFloat logic is wrong: %s", adder.Sum())
+	}
+	if adder.AddFloat(math.NaN(), RoundHalfEven) != ErrInvalidDecimal || adder.AddFloat(math.Inf(-1), RoundHalfEven) != ErrInvalidDecimal {
+		t.Errorf("AddFloat must reject NaN and infinities")
+	}
+	if adder.AddFloat(1e300, RoundHalfEven) != ErrOverflow || adder.Sum() != "1.00" {
+		t.Errorf("AddFloat must reject out of range values")
+	}
+}
+
+func TestFixedPointAdderOverflow(t *testing.T) {
+	adder := NewFixedPointAdder(0)
+	adder.AddUnits(math.MaxInt64)
+	adder.AddUnits(math.MaxInt64)
+	adder.AddUnits(2)
+
+	// sum is still exact
+	if adder.Sum() != "18446744073709551616" {
+		t.Errorf("Sum must be exact: %s", adder.Sum())
+	}
+	if v, err := adder.SumUnits(); err != ErrOverflow || v != math.MaxInt64 || !adder.Overflowed() {
+		t.Errorf("SumUnits must report overflow")
+	}
+
+	adder.Reset()
+	if adder.AddUnits(-5); adder.Sum() != "-5" {
+		t.Errorf("FixedPointAdder logic is wrong")
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	cases := []struct {
+		units int64
+		scale int
+		out   string
+	}{
+		{0, 0, "0"},
+		{0, 2, "0.00"},
+		{5, 2, "0.05"},
+		{-5, 2, "-0.05"},
+		{12345, 2, "123.45"},
+		{-12345, 4, "-1.2345"},
+		{math.MinInt64, 18, "-9.223372036854775808"},
+	}
+
+	for _, c := range cases {
+		if s := formatUnits(bigOf(c.units), c.scale); s != c.out {
+			t.Errorf("formatUnits(%d, %d) = %s", c.units, c.scale, s)
+		}
+	}
+}
+
+func TestNewFixedPointAdderScale(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewFixedPointAdder must panic on invalid scale")
+		}
+	}()
+	NewFixedPointAdder(MaxScale + 1)
+}
@@ -2,4 +2,4 @@ module github.com/linxGnu/go-adder
 
 require github.com/valyala/fastrand v1.0.0
 
-go 1.13
+go 1.18
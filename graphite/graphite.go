@@ -0,0 +1,434 @@
+// Package graphite periodically writes sums of adders to Graphite, over TCP in the plaintext or pickle protocol.
+//
+// Every interval, the exporter reads Sum of each adder and queues a datapoint `path value timestamp`.
+// Queued datapoints are written in batches. While the endpoint is down, the exporter reconnects with
+// exponential backoff and keeps datapoints in a bounded queue, dropping the oldest ones once it is full.
+//
+// Adders are not drained: Graphite receives cumulative sums, use its derivative functions to get rates.
+package graphite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+const (
+	// DefaultInterval is the default interval between datapoints.
+	DefaultInterval = time.Minute
+	// DefaultQueueSize is the default number of datapoints kept while the endpoint is down.
+	DefaultQueueSize = 10000
+	// DefaultBatchSize is the default number of datapoints per write.
+	DefaultBatchSize = 500
+	// DefaultMinBackoff and DefaultMaxBackoff bound the default delay between reconnections.
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// errBackoff is returned when the endpoint is down and the next reconnection is not due yet.
+var errBackoff = errors.New("graphite: endpoint is down, backing off")
+
+// Protocol of Graphite carbon receiver
+type Protocol int
+
+const (
+	// Plaintext protocol writes a `path value timestamp` line per datapoint.
+	Plaintext Protocol = iota
+	// Pickle protocol writes batches of datapoints as length-prefixed pickled lists.
+	Pickle
+)
+
+// Option configures Exporter.
+type Option func(*Exporter)
+
+// WithInterval sets interval between datapoints. Non-positive value keeps DefaultInterval.
+func WithInterval(d time.Duration) Option {
+	return func(e *Exporter) {
+		if d > 0 {
+			e.interval = d
+		}
+	}
+}
+
+// WithPrefix prepends prefix and a dot to every path.
+func WithPrefix(prefix string) Option {
+	return func(e *Exporter) {
+		if prefix != "" {
+			e.prefix = sanitize(strings.TrimSuffix(prefix, ".")) + "."
+		}
+	}
+}
+
+// WithProtocol sets protocol, Plaintext by default.
+func WithProtocol(p Protocol) Option {
+	return func(e *Exporter) {
+		e.protocol = p
+	}
+}
+
+// WithQueueSize sets number of datapoints kept while the endpoint is down. Non-positive value keeps DefaultQueueSize.
+func WithQueueSize(n int) Option {
+	return func(e *Exporter) {
+		if n > 0 {
+			e.queueSize = n
+		}
+	}
+}
+
+// WithBatchSize sets number of datapoints per write. Non-positive value keeps DefaultBatchSize.
+func WithBatchSize(n int) Option {
+	return func(e *Exporter) {
+		if n > 0 {
+			e.batchSize = n
+		}
+	}
+}
+
+// WithBackoff sets bounds of delay between reconnections, which doubles upon each failure.
+// Non-positive values keep DefaultMinBackoff and DefaultMaxBackoff.
+func WithBackoff(min, max time.Duration) Option {
+	return func(e *Exporter) {
+		if min > 0 {
+			e.minBackoff = min
+		}
+		if max > 0 {
+			e.maxBackoff = max
+		}
+	}
+}
+
+// Exporter periodically writes sums of adders to Graphite.
+//
+// Exporter is safe for concurrent use.
+type Exporter struct {
+	addr       string
+	prefix     string
+	protocol   Protocol
+	interval   time.Duration
+	queueSize  int
+	batchSize  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	lock    sync.Mutex // guards sources
+	sources []source
+
+	flush   sync.Mutex // guards queue and connection
+	queue   []datapoint
+	dropped uint64
+	conn    net.Conn
+	backoff time.Duration
+	retryAt time.Time
+	retry   *time.Timer
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+type datapoint struct {
+	path      string
+	value     float64
+	text      string
+	timestamp int64
+}
+
+// source reads adders, emitting a datapoint per adder.
+type source func(emit func(path string, value float64, text string))
+
+// NewExporter create new Exporter writing to the carbon receiver at addr, e.g. "127.0.0.1:2003"
+// for plaintext or "127.0.0.1:2004" for pickle. Connection is made upon the first write.
+// Stop must be called to write the last datapoints and release the background routine.
+func NewExporter(addr string, opts ...Option) *Exporter {
+	e := &Exporter{
+		addr:       addr,
+		interval:   DefaultInterval,
+		queueSize:  DefaultQueueSize,
+		batchSize:  DefaultBatchSize,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		stop:       make(chan struct{}),
+		retry:      time.NewTimer(time.Hour),
+	}
+	e.retry.Stop()
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.maxBackoff < e.minBackoff {
+		e.maxBackoff = e.minBackoff
+	}
+
+	e.wg.Add(1)
+	go e.run()
+
+	return e
+}
+
+// AddLong writes sum of the given long adder at path.
+func (e *Exporter) AddLong(path string, adder goadder.LongAdder) {
+	path = e.prefix + sanitize(path)
+	e.add(func(emit func(path string, value float64, text string)) {
+		v := adder.Sum()
+		emit(path, float64(v), strconv.FormatInt(v, 10))
+	})
+}
+
+// AddFloat writes sum of the given float64 adder at path. NaN and infinite sums are skipped.
+func (e *Exporter) AddFloat(path string, adder goadder.Float64Adder) {
+	path = e.prefix + sanitize(path)
+	e.add(func(emit func(path string, value float64, text string)) {
+		emitFloat(emit, path, adder.Sum())
+	})
+}
+
+// AddMap writes sum of each key of an AdderMap at path, followed by a dot and the key formatted by fmt.Sprint.
+// Keys created later are written as well.
+func AddMap[K comparable](e *Exporter, path string, m *goadder.AdderMap[K]) {
+	path = e.prefix + sanitize(path) + "."
+	e.add(func(emit func(path string, value float64, text string)) {
+		m.Range(func(k K, adder goadder.LongAdder) bool {
+			v := adder.Sum()
+			emit(path+sanitize(fmt.Sprint(k)), float64(v), strconv.FormatInt(v, 10))
+			return true
+		})
+	})
+}
+
+// AddRegistry writes sum of all adders of a registry, at paths of their registry names.
+// Adders registered later are written as well.
+func (e *Exporter) AddRegistry(r *goadder.Registry) {
+	e.add(func(emit func(path string, value float64, text string)) {
+		r.Each(func(entry goadder.Entry) bool {
+			path := e.prefix + sanitize(entry.Name)
+			if entry.Long != nil {
+				v := entry.Long.Sum()
+				emit(path, float64(v), strconv.FormatInt(v, 10))
+			} else {
+				emitFloat(emit, path, entry.Float.Sum())
+			}
+			return true
+		})
+	})
+}
+
+func emitFloat(emit func(path string, value float64, text string), path string, v float64) {
+	if !math.IsNaN(v) && !math.IsInf(v, 0) {
+		emit(path, v, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+}
+
+func (e *Exporter) add(s source) {
+	e.lock.Lock()
+	e.sources = append(e.sources, s)
+	e.lock.Unlock()
+}
+
+// Flush reads adders and writes queued datapoints now, reconnecting if needed. While backing off,
+// it does not reconnect and returns an error. Datapoints failed to be written stay queued.
+func (e *Exporter) Flush() error {
+	e.flush.Lock()
+	defer e.flush.Unlock()
+
+	e.collect(time.Now())
+	return e.send(time.Now())
+}
+
+// Queued returns number of datapoints waiting to be written.
+func (e *Exporter) Queued() int {
+	e.flush.Lock()
+	defer e.flush.Unlock()
+	return len(e.queue)
+}
+
+// Dropped returns number of datapoints dropped because the queue was full.
+func (e *Exporter) Dropped() uint64 {
+	e.flush.Lock()
+	defer e.flush.Unlock()
+	return e.dropped
+}
+
+// Stop background routine, makes a last attempt to write datapoints and closes the connection.
+func (e *Exporter) Stop() (err error) {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+		e.wg.Wait()
+		e.retry.Stop()
+
+		e.flush.Lock()
+		defer e.flush.Unlock()
+
+		e.collect(time.Now())
+		e.retryAt = time.Time{}
+		err = e.send(time.Now())
+		if e.conn != nil {
+			e.conn.Close()
+			e.conn = nil
+		}
+	})
+	return
+}
+
+func (e *Exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			e.flush.Lock()
+			e.collect(time.Now())
+		case <-e.retry.C:
+			e.flush.Lock()
+		}
+
+		_ = e.send(time.Now())
+		e.flush.Unlock()
+	}
+}
+
+// collect queues a datapoint per adder, dropping the oldest datapoints beyond queue size. Caller must hold flush.
+func (e *Exporter) collect(now time.Time) {
+	e.lock.Lock()
+	sources := e.sources
+	e.lock.Unlock()
+
+	ts := now.Unix()
+	for _, s := range sources {
+		s(func(path string, value float64, text string) {
+			e.queue = append(e.queue, datapoint{path: path, value: value, text: text, timestamp: ts})
+		})
+	}
+
+	if over := len(e.queue) - e.queueSize; over > 0 {
+		e.dropped += uint64(over)
+		e.queue = append(e.queue[:0], e.queue[over:]...)
+	}
+}
+
+// send writes queued datapoints in batches, connecting if needed. Caller must hold flush.
+func (e *Exporter) send(now time.Time) error {
+	if len(e.queue) == 0 {
+		return nil
+	}
+
+	if e.conn == nil {
+		if now.Before(e.retryAt) {
+			return errBackoff
+		}
+
+		conn, err := net.DialTimeout("tcp", e.addr, dialTimeout)
+		if err != nil {
+			e.failed(now)
+			return err
+		}
+		e.conn = conn
+	}
+
+	var buf bytes.Buffer
+	for len(e.queue) > 0 {
+		n := e.batchSize
+		if n > len(e.queue) {
+			n = len(e.queue)
+		}
+
+		buf.Reset()
+		if e.protocol == Pickle {
+			encodePickle(&buf, e.queue[:n])
+		} else {
+			encodePlaintext(&buf, e.queue[:n])
+		}
+
+		_ = e.conn.SetWriteDeadline(now.Add(writeTimeout))
+		if _, err := e.conn.Write(buf.Bytes()); err != nil {
+			e.conn.Close()
+			e.conn = nil
+			e.failed(now)
+			return err
+		}
+		e.queue = e.queue[n:]
+	}
+
+	e.queue = nil
+	e.backoff = 0
+	return nil
+}
+
+// failed doubles backoff, starting from min and capped by max, and schedules the next reconnection
+// by the background routine.
+func (e *Exporter) failed(now time.Time) {
+	if e.backoff *= 2; e.backoff < e.minBackoff {
+		e.backoff = e.minBackoff
+	} else if e.backoff > e.maxBackoff {
+		e.backoff = e.maxBackoff
+	}
+	e.retryAt = now.Add(e.backoff)
+	e.retry.Reset(e.backoff)
+}
+
+func encodePlaintext(buf *bytes.Buffer, points []datapoint) {
+	for _, p := range points {
+		buf.WriteString(p.path)
+		buf.WriteByte(' ')
+		buf.WriteString(p.text)
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(p.timestamp, 10))
+		buf.WriteByte('\n')
+	}
+}
+
+// encodePickle writes points as a list of (path, (timestamp, value)) tuples, pickled in protocol 2
+// and prefixed by 4-byte big-endian length, as expected by carbon pickle receiver.
+func encodePickle(buf *bytes.Buffer, points []datapoint) {
+	buf.Write([]byte{0, 0, 0, 0}) // length placeholder
+	buf.WriteString("\x80\x02")   // PROTO 2
+	buf.WriteByte(']')            // EMPTY_LIST
+	buf.WriteByte('(')            // MARK
+
+	var b [8]byte
+	for _, p := range points {
+		buf.WriteByte('X') // BINUNICODE
+		binary.LittleEndian.PutUint32(b[:4], uint32(len(p.path)))
+		buf.Write(b[:4])
+		buf.WriteString(p.path)
+
+		if p.timestamp >= math.MinInt32 && p.timestamp <= math.MaxInt32 {
+			buf.WriteByte('J') // BININT
+			binary.LittleEndian.PutUint32(b[:4], uint32(p.timestamp))
+			buf.Write(b[:4])
+		} else {
+			buf.WriteString("\x8a\x08") // LONG1 of 8 bytes
+			binary.LittleEndian.PutUint64(b[:], uint64(p.timestamp))
+			buf.Write(b[:])
+		}
+
+		buf.WriteByte('G') // BINFLOAT
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(p.value))
+		buf.Write(b[:])
+
+		buf.WriteString("\x86\x86") // TUPLE2 (timestamp, value), then TUPLE2 (path, ...)
+	}
+
+	buf.WriteByte('e') // APPENDS
+	buf.WriteByte('.') // STOP
+
+	binary.BigEndian.PutUint32(buf.Bytes()[:4], uint32(buf.Len()-4))
+}
+
+// sanitize replaces whitespace, which delimits the plaintext protocol, in a path by underscore.
+var sanitize = strings.NewReplacer(" ", "_", "\t", "_", "\n", "_", "\r", "_").Replace
@@ -0,0 +1,233 @@
+package graphite
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+func listen(t *testing.T, addr string) net.Listener {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// accept the next connection of listener, within timeout.
+func accept(t *testing.T, l net.Listener, timeout time.Duration) net.Conn {
+	l.(*net.TCPListener).SetDeadline(time.Now().Add(timeout))
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	return conn
+}
+
+func readLines(t *testing.T, conn net.Conn, n int) (lines []string) {
+	r := bufio.NewReader(conn)
+	for len(lines) < n {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read %q: %v", lines, err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	sort.Strings(lines)
+	return
+}
+
+func testExporter(t *testing.T, addr string, opts ...Option) *Exporter {
+	e := NewExporter(addr, append([]Option{WithInterval(time.Hour)}, opts...)...)
+
+	requests := goadder.NewLongAdder(goadder.JDKAdderType)
+	requests.Add(1027)
+	e.AddLong("http.requests", requests)
+
+	latency := goadder.NewFloat64Adder(goadder.KahanF64AdderType)
+	latency.Add(0.25)
+	e.AddFloat("http.latency seconds", latency)
+	e.AddFloat("skipped", goadder.NewFloat64Adder(goadder.AtomicF64AdderType))
+	e.AddFloat("nan", &nanAdder{goadder.NewFloat64Adder(goadder.AtomicF64AdderType)})
+
+	m := goadder.NewAdderMap[int](goadder.PerPAdderType, 0, 0)
+	m.Add(200, 5)
+	AddMap(e, "responses", m)
+
+	r := goadder.NewRegistry()
+	jobs, _ := r.GetOrCreateLongAdder("jobs.done", goadder.AtomicAdderType)
+	jobs.Add(-3)
+	e.AddRegistry(r)
+
+	return e
+}
+
+// nanAdder sums to NaN.
+type nanAdder struct {
+	goadder.Float64Adder
+}
+
+func (nanAdder) Sum() float64 {
+	return math.NaN()
+}
+
+func checkDatapoint(t *testing.T, actual []string, path, value string) {
+	for _, line := range actual {
+		if fields := strings.Fields(line); len(fields) == 3 && fields[0] == path {
+			ts, _ := strconv.ParseInt(fields[2], 10, 64)
+			if fields[1] != value || time.Since(time.Unix(ts, 0)) > time.Minute {
+				t.Errorf("Datapoint is wrong: %s", line)
+			}
+			return
+		}
+	}
+	t.Errorf("Datapoint %s is missing: %q", path, actual)
+}
+
+func TestExporterPlaintext(t *testing.T) {
+	l := listen(t, "127.0.0.1:0")
+	defer l.Close()
+
+	e := testExporter(t, l.Addr().String(), WithPrefix("app"), WithBatchSize(2))
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := accept(t, l, time.Second)
+	defer conn.Close()
+
+	lines := readLines(t, conn, 5)
+	checkDatapoint(t, lines, "app.http.requests", "1027")
+	checkDatapoint(t, lines, "app.http.latency_seconds", "0.25")
+	checkDatapoint(t, lines, "app.skipped", "0")
+	checkDatapoint(t, lines, "app.responses.200", "5")
+	checkDatapoint(t, lines, "app.jobs.done", "-3")
+
+	// sums are cumulative, written again by Stop
+	if err := e.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	lines = readLines(t, conn, 5)
+	checkDatapoint(t, lines, "app.http.requests", "1027")
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Stop must close connection: %v", err)
+	}
+}
+
+// unpickle decodes a length-prefixed list of (path, (timestamp, value)) tuples, as written by encodePickle.
+func unpickle(t *testing.T, r io.Reader) (lines []string) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b[:4]) != "\x80\x02](" || string(b[len(b)-2:]) != "e." {
+		t.Fatalf("Pickle framing is wrong: %q", b)
+	}
+	for b = b[4 : len(b)-2]; len(b) > 0; {
+		if b[0] != 'X' {
+			t.Fatalf("Pickle is wrong: %q", b)
+		}
+		n := binary.LittleEndian.Uint32(b[1:])
+		path := string(b[5 : 5+n])
+		b = b[5+n:]
+
+		var ts int64
+		if b[0] == 'J' {
+			ts, b = int64(int32(binary.LittleEndian.Uint32(b[1:]))), b[5:]
+		} else if string(b[:2]) == "\x8a\x08" {
+			ts, b = int64(binary.LittleEndian.Uint64(b[2:])), b[10:]
+		} else {
+			t.Fatalf("Pickle is wrong: %q", b)
+		}
+
+		if b[0] != 'G' || string(b[9:11]) != "\x86\x86" {
+			t.Fatalf("Pickle is wrong: %q", b)
+		}
+		v := math.Float64frombits(binary.BigEndian.Uint64(b[1:]))
+		b = b[11:]
+
+		lines = append(lines, path+" "+strconv.FormatFloat(v, 'g', -1, 64)+" "+strconv.FormatInt(ts, 10))
+	}
+	return
+}
+
+func TestExporterPickle(t *testing.T) {
+	l := listen(t, "127.0.0.1:0")
+	defer l.Close()
+
+	e := testExporter(t, l.Addr().String(), WithProtocol(Pickle), WithBatchSize(3))
+	defer e.Stop()
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := accept(t, l, time.Second)
+	defer conn.Close()
+
+	first, second := unpickle(t, conn), unpickle(t, conn)
+	if len(first) != 3 || len(second) != 2 {
+		t.Errorf("Datapoints must be pickled in batches: %q %q", first, second)
+	}
+
+	lines := append(first, second...)
+	checkDatapoint(t, lines, "http.requests", "1027")
+	checkDatapoint(t, lines, "http.latency_seconds", "0.25")
+	checkDatapoint(t, lines, "responses.200", "5")
+	checkDatapoint(t, lines, "jobs.done", "-3")
+
+	var buf bytes.Buffer
+	encodePickle(&buf, []datapoint{{path: "big", value: 1.5, timestamp: math.MaxInt32 + 1}})
+	if lines = unpickle(t, &buf); len(lines) != 1 || lines[0] != "big 1.5 2147483648" {
+		t.Errorf("Pickle of large timestamp is wrong: %q", lines)
+	}
+}
+
+func TestExporterReconnect(t *testing.T) {
+	// reserve an address, down for now
+	l := listen(t, "127.0.0.1:0")
+	addr := l.Addr().String()
+	l.Close()
+
+	e := testExporter(t, addr, WithQueueSize(8), WithBackoff(20*time.Millisecond, 40*time.Millisecond))
+	defer e.Stop()
+
+	if err := e.Flush(); err == nil {
+		t.Fatal("Flush must fail while endpoint is down")
+	}
+	if err := e.Flush(); err != errBackoff {
+		t.Errorf("Flush must back off: %v", err)
+	}
+	if e.Queued() != 8 || e.Dropped() != 2 {
+		t.Errorf("Queue must be bounded: %d queued, %d dropped", e.Queued(), e.Dropped())
+	}
+
+	// endpoint is up again, background routine reconnects after backoff
+	l = listen(t, addr)
+	defer l.Close()
+
+	conn := accept(t, l, 5*time.Second)
+	defer conn.Close()
+
+	lines := readLines(t, conn, 8)
+	checkDatapoint(t, lines, "http.requests", "1027")
+	checkDatapoint(t, lines, "jobs.done", "-3")
+	if e.Queued() != 0 {
+		t.Errorf("Queue must be written")
+	}
+}
@@ -1,24 +1,45 @@
 package goadder
 
 import (
-	"sync/atomic"
+	"math"
+	"math/big"
 )
 
-// JDKAdder is ported version of OpenJDK9 LongAdder.
+// StripedAdder is ported version of OpenJDK9 LongAdder and DoubleAdder, generic over numeric type T.
 //
 // When multiple routines update a common sum that is used for purposes such as collecting statistics,
 // not for fine-grained synchronization control, contention overhead could be a pain.
 //
-// JDKAdder is preferable to atomic, delivers significantly higher throughput under high contention,
+// StripedAdder is preferable to atomic, delivers significantly higher throughput under high contention,
 // at the expense of higher space consumption, while keeping same characteristics under low contention.
 //
 // One or more variables, called Cells, together maintain an initially zero sum. When updates are contended across routines,
 // the set of variables may grow dynamically to reduce contention. In other words, updates are distributed over Cells.
 // The value is lazy, only aggregated (sum) over Cells when needed.
 //
-// JDKAdder is high performance, non-blocking and safe for concurrent use.
-type JDKAdder struct {
-	Striped64
+// StripedAdder is high performance, non-blocking and safe for concurrent use.
+type StripedAdder[T Number] struct {
+	Striped[T]
+	overflow
+}
+
+// JDKAdder is ported version of OpenJDK9 LongAdder, see StripedAdder.
+type JDKAdder = StripedAdder[int64]
+
+// NewStripedAdder create new StripedAdder with given options.
+func NewStripedAdder[T Number](opts ...Option) *StripedAdder[T] {
+	u := &StripedAdder[T]{}
+	u.init(opts)
+	return u
+}
+
+func (u *StripedAdder[T]) init(opts []Option) {
+	u.Striped.init(opts)
+	if !isFloat[T]() {
+		if u.overflow.init(&u.conf); u.overflow.enabled {
+			u.onWrap = u.spill
+		}
+	}
 }
 
 // NewJDKAdder create new JDKAdder
@@ -26,95 +47,187 @@ func NewJDKAdder() *JDKAdder {
 	return &JDKAdder{}
 }
 
+// NewJDKAdderWithOptions create new JDKAdder with given options.
+func NewJDKAdderWithOptions(opts ...Option) *JDKAdder {
+	return NewStripedAdder[int64](opts...)
+}
+
 // Add the given value
-func (u *JDKAdder) Add(x int64) {
+func (u *StripedAdder[T]) Add(x T) {
+	u.add(x, x < 0, 0)
+}
+
+// Inc by 1
+func (u *StripedAdder[T]) Inc() {
+	u.add(1, false, 0)
+}
+
+// Dec by 1
+func (u *StripedAdder[T]) Dec() {
+	var one T = 1
+	u.add(-one, true, 0)
+}
+
+// addProbe adds x using the given non-zero probe instead of a random one.
+func (u *StripedAdder[T]) addProbe(x T, probe int) {
+	u.add(x, x < 0, probe)
+}
+
+// add x which is negative or subtracting by neg. Zero probe is drawn at random when needed.
+func (u *StripedAdder[T]) add(x T, neg bool, probe int) {
 	_as, uncontended := u.cells.Load(), false
 	if _as != nil {
 		uncontended = true
-	} else if b := atomic.LoadInt64(&u.base); !u.casBase(b, b+x) {
+	} else if b := u.base.load(); u.wraps(b, b+x, nil, neg) {
+		u.onWrap(x, neg)
+		return
+	} else if !u.base.cas(b, b+x) {
+		u.contention.baseCASFailed()
 		uncontended = true
 	}
 
 	if uncontended {
 		if _as == nil {
-			u.accumulate(getRandomInt(), x, nil, true)
+			u.accumulate(orRandom(probe), x, nil, neg, true)
 			return
 		}
 
 		as := _as.(cells)
 		m := len(as) - 1
 		if m < 0 {
-			u.accumulate(getRandomInt(), x, nil, true)
+			u.accumulate(orRandom(probe), x, nil, neg, true)
 			return
 		}
 
-		probe := getRandomInt() & m
+		probe = orRandom(probe) & m
 		if _a := as[probe].Load(); _a == nil {
-			u.accumulate(probe, x, nil, uncontended)
+			u.accumulate(probe, x, nil, neg, uncontended)
 		} else {
-			a := _a.(*cell)
+			a := _a.(*cell[T])
 
-			v := atomic.LoadInt64(&a.val)
-			if uncontended = a.cas(v, v+x); !uncontended {
-				u.accumulate(probe, x, nil, uncontended)
+			v := a.load()
+			if u.wraps(v, v+x, nil, neg) {
+				u.onWrap(x, neg)
+			} else if uncontended = a.cas(v, v+x); !uncontended {
+				u.contention.cellCASFailed()
+				u.accumulate(probe, x, nil, neg, uncontended)
+			} else if a.isRetired() {
+				u.sweep(a)
 			}
 		}
 	}
 }
 
-// Inc by 1
-func (u *JDKAdder) Inc() {
-	u.Add(1)
-}
-
-// Dec by 1
-func (u *JDKAdder) Dec() {
-	u.Add(-1)
+// spill takes over an addition which would wrap base or a cell around.
+func (u *StripedAdder[T]) spill(x T, neg bool) {
+	spillInto(&u.overflow, x, neg)
 }
 
 // Sum return the current sum. The returned value is NOT an
 // atomic snapshot because of concurrent update.
-func (u *JDKAdder) Sum() int64 {
-	sum, _as := atomic.LoadInt64(&u.base), u.cells.Load()
+func (u *StripedAdder[T]) Sum() T {
+	sum, _ := u.fold((*cell[T]).load, false, u.overflow.enabled)
+	return sum
+}
+
+// String returns the current sum as JSON.
+func (u *StripedAdder[T]) String() string {
+	return formatJSON(u.Sum())
+}
+
+// SumChecked return the current sum like Sum, but never wraps around silently. If the sum exceeds
+// range of T, it returns the nearest bound of T, i.e. saturating sum, along with ErrOverflow. Float sums
+// are never saturated, ErrOverflow is returned if the sum is infinite.
+//
+// Cells of integer adders may wrap around unless the adder is created with WithOverflowCheck,
+// thus an exact result requires that option.
+func (u *StripedAdder[T]) SumChecked() (T, error) {
+	sum, over := u.fold((*cell[T]).load, false, true)
+	if isFloat[T]() {
+		if math.IsInf(float64(sum), 0) {
+			return sum, ErrOverflow
+		}
+		return sum, nil
+	}
+	return saturate(sum, over)
+}
+
+// Reset variables maintaining the sum to zero. This method may be a useful alternative
+// to creating a new adder. Updates concurrent with this method are either discarded
+// or retained after the reset, never partially applied.
+func (u *StripedAdder[T]) Reset() {
+	u.SumAndReset()
+}
+
+// SumAndReset equivalent in effect to sum followed by reset. Base and each Cell are atomically
+// swapped to zero in place, so it is safe to drain a live adder: every update concurrent with
+// this method is counted in exactly one of the returned value or subsequent sums.
+//
+// The returned value is NOT an atomic snapshot of the adder as a whole.
+func (u *StripedAdder[T]) SumAndReset() T {
+	sum, _ := u.fold(func(c *cell[T]) T { return c.swap(0) }, true, u.overflow.enabled)
+	return sum
+}
+
+// fold sums values got from base and each cell. If check is set, it also counts spilled
+// additions and reports whether the sum exceeds range of T, see settle.
+func (u *StripedAdder[T]) fold(get func(*cell[T]) T, drain, check bool) (sum T, over int) {
+	check = check && !isFloat[T]()
+
+	sum, w := u.sumWide(get, check)
+	if check {
+		return settle[T](&u.overflow, w, drain)
+	}
+	return
+}
+
+// bigSum returns the exact sum of integers got from base and each cell, plus spilled additions
+// which are drained if drain is set.
+func (u *StripedAdder[T]) bigSum(get func(*cell[T]) T, drain bool) *big.Int {
+	_, w := u.sumWide(get, true)
+	return combine(&u.overflow, w, drain)
+}
+
+// sumWide sums values got from base and each cell. If exact is set, they are also summed into w without wrapping around.
+func (u *StripedAdder[T]) sumWide(get func(*cell[T]) T, exact bool) (sum T, w wide) {
+	v, _as := get(&u.base), u.cells.Load()
+	if sum = v; exact {
+		addWide(&w, v)
+	}
+
 	if _as != nil {
 		as := _as.(cells)
 		var a interface{}
 		for i := range as {
 			if a = as[i].Load(); a != nil {
-				sum += atomic.LoadInt64(&a.(*cell).val)
+				v = get(a.(*cell[T]))
+				if sum += v; exact {
+					addWide(&w, v)
+				}
 			}
 		}
 	}
-	return sum
-}
-
-// Reset variables maintaining the sum to zero. This method may be a useful alternative
-// to creating a new adder, but is only effective if there are no concurrent updates.
-// Because this method is intrinsically racy.
-func (u *JDKAdder) Reset() {
-	u.store(0)
-}
-
-// SumAndReset equivalent in effect to sum followed by reset. Like the nature of Sum and Reset,
-// this function is only effective if there are no concurrent updates.
-func (u *JDKAdder) SumAndReset() (sum int64) {
-	sum = u.Sum()
-	u.Reset()
 	return
 }
 
-// Store value. This function is only effective if there are no concurrent updates.
-func (u *JDKAdder) Store(v int64) {
-	u.store(v)
+// Compact folds all cells into base and shrinks cell table back to its initial size,
+// releasing memory held by cells after a burst of contention. The sum is unchanged and
+// it is safe to call concurrently with updates. Cells are created again upon contention.
+func (u *StripedAdder[T]) Compact() {
+	u.compact()
 }
 
-func (u *JDKAdder) store(v int64) {
-	atomic.StoreInt64(&u.base, v)
+// Store value. This function is only effective if there are no concurrent updates.
+func (u *StripedAdder[T]) Store(v T) {
 	if _as := u.cells.Load(); _as != nil {
-		cells := make(cells, len(_as.(cells)))
-		for i := range cells {
-			cells[i].Store(&cell{})
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				a.(*cell[T]).store(0)
+			}
 		}
-		u.cells.Store(cells)
 	}
+	u.overflow.resetSpill()
+	u.base.store(v)
 }
@@ -1,6 +1,8 @@
 package goadder
 
 import (
+	"math"
+	"sync"
 	"testing"
 )
 
@@ -27,3 +29,105 @@ func TestJDKAdderNotRaceAdd(t *testing.T) {
 func TestJDKAdderRaceAdd(t *testing.T) {
 	testAdderRaceAdd(t, JDKAdderType)
 }
+
+func TestJDKAdderRaceDrain(t *testing.T) {
+	testAdderRaceDrain(t, JDKAdderType)
+}
+
+func TestBitsEncoding(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, math.MaxInt64, math.MinInt64} {
+		if fromBits[int64](toBits(v)) != v {
+			t.Errorf("int64 encoding of %d is wrong", v)
+		}
+	}
+	for _, v := range []int32{0, 1, -1, math.MaxInt32, math.MinInt32} {
+		if fromBits[int32](toBits(v)) != v || toBits(v) != uint64(int64(v)) {
+			t.Errorf("int32 encoding of %d is wrong", v)
+		}
+	}
+	for _, v := range []uint64{0, 1, math.MaxUint64} {
+		if fromBits[uint64](toBits(v)) != v {
+			t.Errorf("uint64 encoding of %d is wrong", v)
+		}
+	}
+	for _, v := range []float64{0, -1.5, math.MaxFloat64, math.Inf(-1)} {
+		if fromBits[float64](toBits(v)) != v || toBits(v) != math.Float64bits(v) {
+			t.Errorf("float64 encoding of %v is wrong", v)
+		}
+	}
+
+	if isFloat[int64]() || isFloat[int32]() || isFloat[uint64]() || !isFloat[float64]() {
+		t.Errorf("isFloat is wrong")
+	}
+}
+
+func TestNewAdder(t *testing.T) {
+	if _, ok := NewAdder[int64](AtomicAdderType).(*AtomicAdder); !ok {
+		t.Errorf("NewAdder[int64] must follow NewLongAdder")
+	}
+	if _, ok := NewAdder[float64](AtomicF64AdderType).(*AtomicF64Adder); !ok {
+		t.Errorf("NewAdder[float64] must follow NewFloat64Adder")
+	}
+	if _, ok := NewAdder[uint64](RandomCellAdderType).(*RandomCellUint64Adder); !ok {
+		t.Errorf("NewAdder[uint64] must follow NewUint64Adder")
+	}
+	if _, ok := NewAdder[int32](AtomicAdderType).(*StripedAdder[int32]); !ok {
+		t.Errorf("NewAdder[int32] must be striped")
+	}
+}
+
+func TestStripedAdderUint64(t *testing.T) {
+	testGenericAdderRace[uint64](t)
+
+	adder := NewStripedAdder[uint64]()
+	adder.Store(math.MaxUint64)
+	adder.Inc()
+	if adder.Sum() != 0 {
+		t.Errorf("Adder must wrap around")
+	}
+	adder.Dec()
+	if adder.Sum() != math.MaxUint64 {
+		t.Errorf("Adder must wrap around")
+	}
+}
+
+func TestStripedAdderInt32(t *testing.T) {
+	testGenericAdderRace[int32](t)
+
+	adder := NewStripedAdder[int32]()
+	adder.Store(math.MaxInt32)
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+	adder.Inc()
+	if adder.Sum() != math.MinInt32+1 {
+		t.Errorf("Adder must wrap around: %d", adder.Sum())
+	}
+
+	adder.Compact()
+	adder.Add(-2)
+	if adder.Sum() != math.MaxInt32 || adder.SumAndReset() != math.MaxInt32 || adder.Sum() != 0 {
+		t.Errorf("Adder logic is wrong: %d", adder.Sum())
+	}
+}
+
+func testGenericAdderRace[T Number](t *testing.T) {
+	adder := NewAdder[T](JDKAdderType)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Inc()
+				adder.Add(2)
+				adder.Dec()
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	tmp := T(delta/10) * 2 * T(numRoutine)
+	if adder.Sum() != tmp || adder.SumAndReset() != tmp || adder.Sum() != 0 {
+		t.Errorf("Adder logic is wrong")
+	}
+}
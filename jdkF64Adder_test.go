@@ -27,3 +27,7 @@ func TestJDKF64AdderNotRaceAdd(t *testing.T) {
 func TestJDKF64AdderRaceAdd(t *testing.T) {
 	testF64AdderRaceAdd(t, JDKF64AdderType)
 }
+
+func TestJDKF64AdderRaceDrain(t *testing.T) {
+	testF64AdderRaceDrain(t, JDKF64AdderType)
+}
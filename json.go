@@ -0,0 +1,38 @@
+package goadder
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// Adders, accumulators and AdderMap implement String returning their sum as JSON, so that each of them
+// is an expvar.Var and could be published by expvar.Publish as is, see package expvars.
+
+// formatJSON formats v as a JSON number. Since JSON has no NaN nor infinity, they are
+// formatted as strings "NaN", "+Inf" and "-Inf".
+func formatJSON[T Number](v T) string {
+	if !isFloat[T]() {
+		if T(0)-1 > 0 { // unsigned
+			return strconv.FormatUint(uint64(v), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	}
+
+	switch f := float64(v); {
+	case math.IsNaN(f):
+		return `"NaN"`
+	case math.IsInf(f, 1):
+		return `"+Inf"`
+	case math.IsInf(f, -1):
+		return `"-Inf"`
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// quoteJSON formats s as a JSON string.
+func quoteJSON(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,79 @@
+package goadder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	for _, ty := range longAdderTypes {
+		adder := NewLongAdder(ty)
+		adder.Add(-1234)
+		if s := adder.(fmt.Stringer).String(); s != "-1234" {
+			t.Errorf("Adder(%d) String is wrong: %s", ty, s)
+		}
+	}
+
+	for _, ty := range []Type{JDKF64AdderType, AtomicF64AdderType, KahanF64AdderType} {
+		adder := NewFloat64Adder(ty)
+		for _, c := range []struct {
+			x        float64
+			expected string
+		}{
+			{0.1, "0.1"},
+			{-1e300, "-1e+300"},
+			{math.Inf(1), `"+Inf"`},
+			{math.Inf(-1), `"-Inf"`},
+			{math.NaN(), `"NaN"`},
+		} {
+			adder.Store(c.x)
+			if s := adder.(fmt.Stringer).String(); s != c.expected || !json.Valid([]byte(s)) {
+				t.Errorf("Adder(%d) String is wrong: %s", ty, s)
+			}
+		}
+	}
+
+	u := NewUint64Adder(AtomicAdderType)
+	u.Store(math.MaxUint64)
+	f := NewFixedPointAdder(2)
+	f.AddUnits(-5)
+	b := NewBigAdder()
+	b.AddBig(new(big.Int).Lsh(big.NewInt(1), 70))
+	acc := NewLongAccumulator(MaxOp, math.MinInt64)
+	acc.Accumulate(7)
+	e := NewExemplarAdder[int32](JDKAdderType)
+	e.AddWithExemplar(-3, "abc")
+
+	for _, c := range []struct {
+		v        fmt.Stringer
+		expected string
+	}{
+		{u.(fmt.Stringer), "18446744073709551615"},
+		{f, "-0.05"},
+		{b, "1180591620717411303424"},
+		{acc, "7"},
+		{e, "-3"},
+	} {
+		if s := c.v.String(); s != c.expected || !json.Valid([]byte(s)) {
+			t.Errorf("String is wrong: %s, expected %s", s, c.expected)
+		}
+	}
+}
+
+func TestAdderMapString(t *testing.T) {
+	m := NewAdderMap[string](JDKAdderType, 0, "")
+	if m.String() != "{}" {
+		t.Errorf("AdderMap String is wrong: %s", m.String())
+	}
+
+	m.Add("b", 2)
+	m.Add("a\"quoted\"\n", -1)
+
+	var decoded map[string]int64
+	if err := json.Unmarshal([]byte(m.String()), &decoded); err != nil || len(decoded) != 2 || decoded["b"] != 2 || decoded["a\"quoted\"\n"] != -1 {
+		t.Errorf("AdderMap String is wrong: %s", m.String())
+	}
+}
@@ -0,0 +1,209 @@
+package goadder
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+)
+
+// KahanF64Adder is a striped float64 adder with compensated (Kahan-Babuska-Neumaier) summation.
+//
+// JDKF64Adder and AtomicF64Adder add values naively, so adding many small values to a large running
+// total loses their low bits. KahanF64Adder keeps a compensation term beside the sum of base and each
+// Cell, which captures the rounding error of every addition. Sum merges (sum, compensation) pairs
+// of all Cells. For n additions of x with exact sum S, the error of the result is then bounded by
+// 2ε|S| + O(nε²)Σ|x|, instead of O(nε)Σ|x| of naive summation, where ε is 2^-53.
+//
+// Since sum and compensation must be updated together, each Cell is guarded by a tiny spinlock instead
+// of a CAS. An update never waits for the lock: it moves to another Cell instead, just like a failed
+// CAS in JDKF64Adder, and the table of Cells grows upon contention, see WithInitialCells and WithMaxCells.
+//
+// KahanF64Adder is a bit slower than JDKF64Adder, high performance and safe for concurrent use.
+type KahanF64Adder struct {
+	table
+	base kahanCell
+}
+
+// NewKahanF64Adder create new KahanF64Adder with given options.
+func NewKahanF64Adder(opts ...Option) *KahanF64Adder {
+	return &KahanF64Adder{table: table{conf: newConfig(opts)}}
+}
+
+// kahan is a compensated sum, the true sum is approximated by sum + comp.
+type kahan struct {
+	sum, comp float64
+}
+
+// add x using Neumaier variant of Kahan summation, which also holds when |x| > |sum|.
+func (k *kahan) add(x float64) {
+	t := k.sum + x
+	if math.Abs(k.sum) >= math.Abs(x) {
+		k.comp += (k.sum - t) + x
+	} else {
+		k.comp += (x - t) + k.sum
+	}
+	k.sum = t
+}
+
+// merge other compensated sum into k.
+func (k *kahan) merge(o kahan) {
+	k.add(o.sum)
+	k.comp += o.comp
+}
+
+func (k *kahan) value() float64 {
+	if math.IsInf(k.sum, 0) { // compensation is NaN
+		return k.sum
+	}
+	return k.sum + k.comp
+}
+
+type kahanCell struct {
+	_    [7]uint64
+	lock int32
+	kahan
+	_ [7]uint64
+}
+
+func (c *kahanCell) tryLock() bool {
+	return atomic.CompareAndSwapInt32(&c.lock, 0, 1)
+}
+
+func (c *kahanCell) spinLock() {
+	for i := 1; !c.tryLock(); i++ {
+		if i&63 == 0 {
+			runtime.Gosched()
+		}
+	}
+}
+
+func (c *kahanCell) unlock() {
+	atomic.StoreInt32(&c.lock, 0)
+}
+
+// tryAdd adds x unless cell is locked by another routine.
+func (c *kahanCell) tryAdd(x float64) bool {
+	if !c.tryLock() {
+		return false
+	}
+	c.add(x)
+	c.unlock()
+	return true
+}
+
+// swap returns compensated sum of cell and replaces it with v.
+func (c *kahanCell) swap(v kahan) (old kahan) {
+	c.spinLock()
+	old, c.kahan = c.kahan, v
+	c.unlock()
+	return
+}
+
+func (c *kahanCell) get() (v kahan) {
+	c.spinLock()
+	v = c.kahan
+	c.unlock()
+	return
+}
+
+// Add the given value
+func (k *KahanF64Adder) Add(x float64) {
+	k.addProbe(x, 0)
+}
+
+// addProbe adds x using the given probe, or a random one if probe is zero.
+func (k *KahanF64Adder) addProbe(x float64, probe int) {
+	if k.cells.Load() == nil && k.base.tryAdd(x) {
+		return
+	}
+	k.accumulate(orRandom(probe), x)
+}
+
+// Inc by 1
+func (k *KahanF64Adder) Inc() {
+	k.Add(1)
+}
+
+// Dec by 1
+func (k *KahanF64Adder) Dec() {
+	k.Add(-1)
+}
+
+// accumulate adds x to base or a cell upon contention, see Striped.
+func (k *KahanF64Adder) accumulate(probe int, x float64) {
+	accumulate[kahanCell](&k.table, probe, kahanUpdate{k: k, x: x}, true)
+}
+
+// kahanUpdate adds x to base or a cell of KahanF64Adder. Locking a cell never waits, a locked cell
+// counts as contended.
+type kahanUpdate struct {
+	k *KahanF64Adder
+	x float64
+}
+
+func (u kahanUpdate) tryBase() bool {
+	return u.k.base.tryAdd(u.x)
+}
+
+func (u kahanUpdate) tryCell(c *kahanCell) bool {
+	return c.tryAdd(u.x)
+}
+
+func (u kahanUpdate) newCell() *kahanCell {
+	c := &kahanCell{}
+	c.add(u.x)
+	return c
+}
+
+// Sum return the current sum. The returned value is NOT an
+// atomic snapshot because of concurrent update.
+func (k *KahanF64Adder) Sum() float64 {
+	return k.fold((*kahanCell).get)
+}
+
+// String returns the current sum as JSON.
+func (k *KahanF64Adder) String() string {
+	return formatJSON(k.Sum())
+}
+
+// Reset variables maintaining the sum to zero. Updates concurrent with this method are either
+// discarded or retained after the reset, never partially applied.
+func (k *KahanF64Adder) Reset() {
+	k.SumAndReset()
+}
+
+// SumAndReset equivalent in effect to sum followed by reset. Base and each Cell are atomically
+// swapped to zero in place, so it is safe to drain a live adder: every update concurrent with
+// this method is counted in exactly one of the returned value or subsequent sums.
+func (k *KahanF64Adder) SumAndReset() float64 {
+	return k.fold(func(c *kahanCell) kahan { return c.swap(kahan{}) })
+}
+
+// Store value. This function is only effective if there are no concurrent updates.
+func (k *KahanF64Adder) Store(v float64) {
+	if _as := k.cells.Load(); _as != nil {
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				a.(*kahanCell).swap(kahan{})
+			}
+		}
+	}
+	k.base.swap(kahan{sum: v})
+}
+
+// fold merges compensated sums got from base and each cell.
+func (k *KahanF64Adder) fold(get func(*kahanCell) kahan) float64 {
+	sum, _as := get(&k.base), k.cells.Load()
+	if _as != nil {
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				sum.merge(get(a.(*kahanCell)))
+			}
+		}
+	}
+	return sum.value()
+}
@@ -0,0 +1,123 @@
+package goadder
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestKahanF64AdderNotRaceInc(t *testing.T) {
+	testF64AdderNotRaceInc(t, KahanF64AdderType)
+}
+
+func TestKahanF64AdderRaceInc(t *testing.T) {
+	testF64AdderRaceInc(t, KahanF64AdderType)
+}
+
+func TestKahanF64AdderNotRaceDec(t *testing.T) {
+	testF64AdderNotRaceDec(t, KahanF64AdderType)
+}
+
+func TestKahanF64AdderRaceDec(t *testing.T) {
+	testF64AdderRaceDec(t, KahanF64AdderType)
+}
+
+func TestKahanF64AdderNotRaceAdd(t *testing.T) {
+	testF64AdderNotRaceAdd(t, KahanF64AdderType)
+}
+
+func TestKahanF64AdderRaceAdd(t *testing.T) {
+	testF64AdderRaceAdd(t, KahanF64AdderType)
+}
+
+func TestKahanF64AdderRaceDrain(t *testing.T) {
+	testF64AdderRaceDrain(t, KahanF64AdderType)
+}
+
+// kahanError returns absolute error of sum against exact and the error bound 2ε|S| + nε²Σ|x|
+// of compensated summation.
+func kahanError(sum float64, exact, abs *big.Float, n int) (err, bound float64) {
+	e, _ := new(big.Float).Sub(big.NewFloat(sum), exact).Float64()
+	s, _ := exact.Float64()
+	a, _ := abs.Float64()
+
+	const eps = 1.0 / (1 << 53)
+	return math.Abs(e), 2*eps*math.Abs(s) + float64(n)*eps*eps*a
+}
+
+func TestKahanF64AdderPrecision(t *testing.T) {
+	kahan, naive := NewKahanF64Adder(), NewJDKF64Adder()
+	exact, abs := new(big.Float).SetPrec(2048), new(big.Float).SetPrec(2048)
+
+	add := func(x float64) {
+		kahan.Add(x)
+		naive.Add(x)
+		exact.Add(exact, big.NewFloat(x))
+		abs.Add(abs, big.NewFloat(math.Abs(x)))
+	}
+
+	// many cents on top of a large total
+	n := 1000001
+	add(1e15)
+	for i := 1; i < n; i++ {
+		add(0.01)
+	}
+
+	if err, bound := kahanError(kahan.Sum(), exact, abs, n); err > bound {
+		t.Errorf("Compensated error %g exceeds bound %g", err, bound)
+	}
+	if err, bound := kahanError(naive.Sum(), exact, abs, n); err <= bound {
+		t.Errorf("Naive error %g is expected to exceed bound %g", err, bound)
+	}
+}
+
+func TestKahanF64AdderCancellation(t *testing.T) {
+	adder := NewKahanF64Adder()
+	adder.Add(1)
+	adder.Add(1e100)
+	adder.Add(1)
+	adder.Add(-1e100)
+	if adder.Sum() != 2 {
+		t.Errorf("Compensated sum is wrong: %g", adder.Sum())
+	}
+
+	adder.Add(math.Inf(1))
+	if !math.IsInf(adder.Sum(), 1) {
+		t.Errorf("Compensated sum must be infinite")
+	}
+}
+
+func TestKahanF64AdderRacePrecision(t *testing.T) {
+	adder := NewKahanF64Adder(WithInitialCells(16))
+	adder.Add(1e12)
+
+	values := []float64{0.1, 0.01, 1e-3, 3.3, -0.07}
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Add(values[j%len(values)])
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	exact, abs := new(big.Float).SetPrec(2048), new(big.Float).SetPrec(2048)
+	exact.Add(exact, big.NewFloat(1e12))
+	abs.Add(abs, big.NewFloat(1e12))
+	n := 1 + numRoutine*(delta/10)
+	for i := 0; i < numRoutine; i++ {
+		for j := 0; j < delta/10; j++ {
+			x := values[j%len(values)]
+			exact.Add(exact, big.NewFloat(x))
+			abs.Add(abs, big.NewFloat(math.Abs(x)))
+		}
+	}
+
+	if err, bound := kahanError(adder.Sum(), exact, abs, n); err > bound {
+		t.Errorf("Compensated error %g exceeds bound %g", err, bound)
+	}
+}
@@ -0,0 +1,119 @@
+package goadder
+
+import (
+	"time"
+)
+
+const (
+	defaultFlushEvery = 1024
+
+	// clockCheckMask tells how often LocalCounter looks at the clock: once every 64 updates.
+	clockCheckMask = 63
+)
+
+// LocalOption configures LocalCounter.
+type LocalOption func(*LocalCounter)
+
+// WithFlushEvery sets number of updates after which LocalCounter flushes into its shared adder.
+// Non-positive value keeps the default of 1024.
+func WithFlushEvery(n int) LocalOption {
+	return func(l *LocalCounter) {
+		if n > 0 {
+			l.every = n
+		}
+	}
+}
+
+// WithFlushInterval makes LocalCounter flush into its shared adder once d elapsed since
+// the last flush. Non-positive value disables it, which is the default.
+func WithFlushInterval(d time.Duration) LocalOption {
+	return func(l *LocalCounter) {
+		if d > 0 {
+			l.interval = d
+		}
+	}
+}
+
+// LocalCounter is a handle owned by a single routine, accumulating updates locally without any
+// atomic operation and flushing them into a shared LongAdder. It suits the hottest loops, where even
+// an uncontended JDKAdder.Add costs too much.
+//
+// Pending updates are flushed every N updates (see WithFlushEvery), once an interval elapsed since
+// the last flush (see WithFlushInterval), and on Flush or Close.
+//
+// Staleness bound: Sum of the shared adder misses only pending updates of each LocalCounter, that is
+// fewer than N updates and, with an interval, updates made within about the last interval. The clock
+// is looked at every 64 updates, so the interval is exceeded by the time these updates take. Flushes
+// only happen inside methods of LocalCounter: if the owner stops updating, pending updates stay
+// invisible until Flush or Close. So the owner should Close the counter when done, e.g. by defer.
+//
+// LocalCounter is NOT safe for concurrent use, but the shared adder is.
+type LocalCounter struct {
+	adder    LongAdder
+	pending  int64
+	updates  int
+	every    int
+	interval time.Duration
+	last     time.Time
+	closed   bool
+}
+
+// NewLocalCounter create new LocalCounter flushing into the given adder.
+func NewLocalCounter(adder LongAdder, opts ...LocalOption) *LocalCounter {
+	l := &LocalCounter{adder: adder, every: defaultFlushEvery}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.interval > 0 {
+		l.last = time.Now()
+	}
+	return l
+}
+
+// Add the given value
+func (l *LocalCounter) Add(x int64) {
+	if l.closed {
+		l.adder.Add(x)
+		return
+	}
+
+	l.pending += x
+	if l.updates++; l.updates >= l.every {
+		l.Flush()
+	} else if l.interval > 0 && l.updates&clockCheckMask == 0 && time.Since(l.last) >= l.interval {
+		l.Flush()
+	}
+}
+
+// Inc by 1
+func (l *LocalCounter) Inc() {
+	l.Add(1)
+}
+
+// Dec by 1
+func (l *LocalCounter) Dec() {
+	l.Add(-1)
+}
+
+// Pending returns sum of updates not flushed yet.
+func (l *LocalCounter) Pending() int64 {
+	return l.pending
+}
+
+// Flush pending updates into the shared adder.
+func (l *LocalCounter) Flush() {
+	if l.pending != 0 {
+		l.adder.Add(l.pending)
+		l.pending = 0
+	}
+	l.updates = 0
+	if l.interval > 0 {
+		l.last = time.Now()
+	}
+}
+
+// Close flushes pending updates. Updates after Close are made to the shared adder directly.
+func (l *LocalCounter) Close() {
+	l.Flush()
+	l.closed = true
+}
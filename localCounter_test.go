@@ -0,0 +1,82 @@
+package goadder
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLocalCounterRace(t *testing.T) {
+	adder := NewLongAdder(JDKAdderType)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			local := NewLocalCounter(adder, WithFlushEvery(100))
+			defer local.Close()
+
+			for j := 0; j < delta/10; j++ {
+				local.Add(2)
+				local.Dec()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if expected := int64(delta/10) * int64(numRoutine); adder.Sum() != expected {
+		t.Errorf("LocalCounter logic is wrong: %d, expected %d", adder.Sum(), expected)
+	}
+}
+
+func TestLocalCounterFlushEvery(t *testing.T) {
+	adder := NewAtomicAdder()
+	local := NewLocalCounter(adder, WithFlushEvery(10))
+
+	for i := 0; i < 9; i++ {
+		local.Inc()
+	}
+	if adder.Sum() != 0 || local.Pending() != 9 {
+		t.Errorf("LocalCounter must not flush before N updates")
+	}
+
+	local.Inc()
+	if adder.Sum() != 10 || local.Pending() != 0 {
+		t.Errorf("LocalCounter must flush every N updates")
+	}
+
+	local.Add(5)
+	if local.Flush(); adder.Sum() != 15 || local.Pending() != 0 {
+		t.Errorf("LocalCounter must flush on Flush")
+	}
+
+	local.Add(3)
+	if local.Close(); adder.Sum() != 18 {
+		t.Errorf("LocalCounter must flush on Close")
+	}
+	if local.Add(2); adder.Sum() != 20 || local.Pending() != 0 {
+		t.Errorf("LocalCounter must write through after Close")
+	}
+}
+
+func TestLocalCounterFlushInterval(t *testing.T) {
+	adder := NewAtomicAdder()
+	local := NewLocalCounter(adder, WithFlushEvery(1<<30), WithFlushInterval(20*time.Millisecond))
+
+	for i := 0; i < 64; i++ {
+		local.Inc()
+	}
+	if adder.Sum() != 0 {
+		t.Errorf("LocalCounter must not flush before interval")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	for i := 0; i < 64; i++ {
+		local.Inc()
+	}
+	if adder.Sum() != 128 || local.Pending() != 0 {
+		t.Errorf("LocalCounter must flush after interval: %d", adder.Sum())
+	}
+}
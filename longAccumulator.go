@@ -0,0 +1,128 @@
+package goadder
+
+// Accumulator is ported version of OpenJDK9 LongAccumulator and DoubleAccumulator, generic over numeric type T.
+//
+// One or more variables, called Cells, together maintain a running value updated using
+// a supplied function. When updates are contended across routines, the set of variables may grow
+// dynamically to reduce contention. Get returns the current value combined across the variables
+// maintaining updates.
+//
+// This type is preferable to atomic when multiple routines update a common value that is used for
+// purposes such as collecting statistics, not for fine-grained synchronization control. For example,
+// a striped maximum could be kept by NewLongAccumulator(MaxOp, math.MinInt64).
+//
+// The order of accumulation within or across routines is not guaranteed and cannot be depended upon,
+// so the supplied function should be associative, commutative and side-effect free. The identity
+// must be an identity element of the function: op.Apply(identity, x) == x for all x.
+//
+// Accumulator is high performance, non-blocking and safe for concurrent use.
+type Accumulator[T Number] struct {
+	Striped[T]
+	op BinaryOperator[T]
+}
+
+// LongAccumulator is ported version of OpenJDK9 LongAccumulator, see Accumulator.
+type LongAccumulator = Accumulator[int64]
+
+// NewAccumulator create new Accumulator with given accumulator function and identity element.
+// The op must not be nil.
+func NewAccumulator[T Number](op BinaryOperator[T], identity T) *Accumulator[T] {
+	acc := &Accumulator[T]{op: op}
+	acc.base.store(identity)
+	acc.identity = identity
+	return acc
+}
+
+// NewLongAccumulator create new LongAccumulator with given accumulator function and identity element.
+// The op must not be nil.
+func NewLongAccumulator(op LongBinaryOperator, identity int64) *LongAccumulator {
+	return NewAccumulator(op, identity)
+}
+
+// Accumulate updates with the given value.
+func (l *Accumulator[T]) Accumulate(x T) {
+	_as := l.cells.Load()
+	if _as == nil {
+		b := l.base.load()
+		if r := l.op.Apply(b, x); r == b || l.base.cas(b, r) {
+			return
+		}
+		l.contention.baseCASFailed()
+		l.accumulate(getRandomInt(), x, l.op, false, true)
+		return
+	}
+
+	as := _as.(cells)
+	m := len(as) - 1
+	if m < 0 {
+		l.accumulate(getRandomInt(), x, l.op, false, true)
+		return
+	}
+
+	probe := getRandomInt() & m
+	if _a := as[probe].Load(); _a == nil {
+		l.accumulate(probe, x, l.op, false, true)
+	} else {
+		a := _a.(*cell[T])
+
+		v := a.load()
+		if r := l.op.Apply(v, x); r != v && !a.cas(v, r) {
+			l.contention.cellCASFailed()
+			l.accumulate(probe, x, l.op, false, false)
+		}
+	}
+}
+
+// Get returns the current value. The returned value is NOT an
+// atomic snapshot because of concurrent update.
+func (l *Accumulator[T]) Get() T {
+	result, _as := l.base.load(), l.cells.Load()
+	if _as != nil {
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				result = l.op.Apply(result, a.(*cell[T]).load())
+			}
+		}
+	}
+	return result
+}
+
+// String returns the current value as JSON.
+func (l *Accumulator[T]) String() string {
+	return formatJSON(l.Get())
+}
+
+// Reset variables maintaining updates to the identity value. This method may be a useful alternative
+// to creating a new accumulator, but is only effective if there are no concurrent updates.
+// Because this method is intrinsically racy.
+func (l *Accumulator[T]) Reset() {
+	l.base.store(l.identity)
+	if _as := l.cells.Load(); _as != nil {
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				a.(*cell[T]).store(l.identity)
+			}
+		}
+	}
+}
+
+// GetThenReset equivalent in effect to get followed by reset. Each variable is atomically swapped
+// with the identity value, so an update concurrent with this method is either reflected in the
+// returned value or retained for the next one.
+func (l *Accumulator[T]) GetThenReset() T {
+	result, _as := l.base.swap(l.identity), l.cells.Load()
+	if _as != nil {
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				result = l.op.Apply(result, a.(*cell[T]).swap(l.identity))
+			}
+		}
+	}
+	return result
+}
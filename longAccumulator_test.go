@@ -0,0 +1,90 @@
+package goadder
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestLongAccumulatorNotRace(t *testing.T) {
+	acc := NewLongAccumulator(MaxOp, math.MinInt64)
+	if acc.Get() != math.MinInt64 {
+		t.Errorf("LongAccumulator identity is wrong")
+	}
+
+	for i := 0; i < delta; i++ {
+		acc.Accumulate(int64(i % 1000))
+	}
+
+	if acc.Get() != 999 || acc.GetThenReset() != 999 || acc.Get() != math.MinInt64 {
+		t.Errorf("LongAccumulator logic is wrong")
+	}
+
+	acc.Accumulate(-5)
+	if acc.Get() != -5 {
+		t.Errorf("LongAccumulator logic is wrong")
+	}
+
+	acc.Reset()
+	if acc.Get() != math.MinInt64 {
+		t.Errorf("LongAccumulator logic is wrong")
+	}
+}
+
+func TestLongAccumulatorRace(t *testing.T) {
+	acc := NewLongAccumulator(MaxOp, math.MinInt64)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < delta; j++ {
+				acc.Accumulate(int64(j * (i + 1)))
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	tmp := int64(delta-1) * int64(numRoutine)
+	if acc.Get() != tmp || acc.GetThenReset() != tmp || acc.Get() != math.MinInt64 {
+		t.Errorf("LongAccumulator logic is wrong")
+	}
+
+	// new cells must start from identity after reset
+	acc.Reset()
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta; j++ {
+				acc.Accumulate(-int64(j))
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if acc.Get() != 0 {
+		t.Errorf("LongAccumulator logic is wrong")
+	}
+}
+
+func TestLongAccumulatorBitwiseOr(t *testing.T) {
+	acc := NewLongAccumulator(OrOp, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 63; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < 1000; j++ {
+				acc.Accumulate(1 << uint(i))
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	if acc.Get() != math.MaxInt64 {
+		t.Errorf("LongAccumulator logic is wrong")
+	}
+}
@@ -8,17 +8,25 @@ import (
 type MutexAdder struct {
 	value int64
 	lock  sync.RWMutex
+	overflow
 }
 
 // NewMutexAdder create new MutexAdder
-func NewMutexAdder() *MutexAdder {
-	return &MutexAdder{}
+func NewMutexAdder(opts ...Option) *MutexAdder {
+	m := &MutexAdder{}
+	c := newConfig(opts)
+	m.overflow.init(&c)
+	return m
 }
 
 // Add the given value
 func (m *MutexAdder) Add(x int64) {
 	m.lock.Lock()
-	m.value += x
+	if v := m.value + x; m.overflow.enabled && wrapped(m.value, v, x < 0) {
+		spillInto(&m.overflow, x, x < 0)
+	} else {
+		m.value = v
+	}
 	m.lock.Unlock()
 }
 
@@ -36,25 +44,40 @@ func (m *MutexAdder) Dec() {
 // atomic snapshot because of concurrent update.
 func (m *MutexAdder) Sum() (sum int64) {
 	m.lock.RLock()
-	sum = m.value
+	sum, _ = m.settle(false, m.overflow.enabled)
 	m.lock.RUnlock()
 	return
 }
 
+// String returns the current sum as JSON.
+func (m *MutexAdder) String() string {
+	return formatJSON(m.Sum())
+}
+
+// SumChecked return the current sum like Sum, but never wraps around silently. If the sum exceeds
+// range of int64, it returns the nearest bound of int64, i.e. saturating sum, along with ErrOverflow.
+//
+// The value may wrap around unless the adder is created with WithOverflowCheck,
+// thus an exact result requires that option.
+func (m *MutexAdder) SumChecked() (int64, error) {
+	m.lock.RLock()
+	sum, over := m.settle(false, true)
+	m.lock.RUnlock()
+	return saturate(sum, over)
+}
+
 // Reset variables maintaining the sum to zero. This method may be a useful alternative
 // to creating a new adder, but is only effective if there are no concurrent updates.
 // Because this method is intrinsically racy.
 func (m *MutexAdder) Reset() {
-	m.lock.Lock()
-	m.value = 0
-	m.lock.Unlock()
+	m.Store(0)
 }
 
-// SumAndReset equivalent in effect to sum followed by reset. Like the nature of Sum and Reset,
-// this function is only effective if there are no concurrent updates.
+// SumAndReset equivalent in effect to sum followed by reset. The value is read and reset under lock,
+// so every update concurrent with this method is counted in exactly one of the returned value or subsequent sums.
 func (m *MutexAdder) SumAndReset() (sum int64) {
 	m.lock.Lock()
-	sum = m.value
+	sum, _ = m.settle(true, m.overflow.enabled)
 	m.value = 0
 	m.lock.Unlock()
 	return
@@ -63,6 +86,20 @@ func (m *MutexAdder) SumAndReset() (sum int64) {
 // Store value. This function is only effective if there are no concurrent updates.
 func (m *MutexAdder) Store(v int64) {
 	m.lock.Lock()
+	if m.overflow.enabled {
+		m.overflow.resetSpill()
+	}
 	m.value = v
 	m.lock.Unlock()
 }
+
+// settle combines value with spilled additions if check is set, see settle.
+// Lock must be held.
+func (m *MutexAdder) settle(drain, check bool) (int64, int) {
+	if !check {
+		return m.value, 0
+	}
+	var w wide
+	addWide(&w, m.value)
+	return settle[int64](&m.overflow, w, drain)
+}
@@ -27,3 +27,7 @@ func TestMutexAdderNotRaceAdd(t *testing.T) {
 func TestMutexAdderRaceAdd(t *testing.T) {
 	testAdderRaceAdd(t, MutexAdderType)
 }
+
+func TestMutexAdderRaceDrain(t *testing.T) {
+	testAdderRaceDrain(t, MutexAdderType)
+}
@@ -0,0 +1,117 @@
+package goadder
+
+import (
+	"math"
+)
+
+var (
+	// MaxOp returns the greater of two int64 values.
+	MaxOp LongBinaryOperator = maxOp{}
+	// MinOp returns the smaller of two int64 values.
+	MinOp LongBinaryOperator = minOp{}
+	// OrOp returns the bitwise OR of two int64 values.
+	OrOp LongBinaryOperator = orOp{}
+	// AndOp returns the bitwise AND of two int64 values.
+	AndOp LongBinaryOperator = andOp{}
+	// XorOp returns the bitwise XOR of two int64 values.
+	XorOp LongBinaryOperator = xorOp{}
+	// SaturatingAddOp returns the sum of two int64 values, clamped to [math.MinInt64, math.MaxInt64]
+	// instead of wrapping around.
+	SaturatingAddOp LongBinaryOperator = saturatingAddOp{}
+
+	// MaxF64Op returns the greater of two float64 values. NaN operands are ignored: if exactly one
+	// operand is NaN, the other one is returned; NaN is returned only if both are NaN.
+	// +0 is considered greater than -0.
+	MaxF64Op FloatBinaryOperator = maxF64Op{}
+	// MinF64Op returns the smaller of two float64 values. NaN operands are ignored: if exactly one
+	// operand is NaN, the other one is returned; NaN is returned only if both are NaN.
+	// -0 is considered smaller than +0.
+	MinF64Op FloatBinaryOperator = minF64Op{}
+)
+
+// BinaryFunc is an adapter to allow the use of ordinary functions as BinaryOperator.
+type BinaryFunc[T Number] func(left, right T) T
+
+// Apply calls f(left, right).
+func (f BinaryFunc[T]) Apply(left, right T) T {
+	return f(left, right)
+}
+
+// LongBinaryFunc is an adapter to allow the use of ordinary functions as LongBinaryOperator.
+type LongBinaryFunc = BinaryFunc[int64]
+
+// FloatBinaryFunc is an adapter to allow the use of ordinary functions as FloatBinaryOperator.
+type FloatBinaryFunc = BinaryFunc[float64]
+
+type maxOp struct{}
+
+func (maxOp) Apply(left, right int64) int64 {
+	if left >= right {
+		return left
+	}
+	return right
+}
+
+type minOp struct{}
+
+func (minOp) Apply(left, right int64) int64 {
+	if left <= right {
+		return left
+	}
+	return right
+}
+
+type orOp struct{}
+
+func (orOp) Apply(left, right int64) int64 {
+	return left | right
+}
+
+type andOp struct{}
+
+func (andOp) Apply(left, right int64) int64 {
+	return left & right
+}
+
+type xorOp struct{}
+
+func (xorOp) Apply(left, right int64) int64 {
+	return left ^ right
+}
+
+type saturatingAddOp struct{}
+
+func (saturatingAddOp) Apply(left, right int64) int64 {
+	sum := left + right
+	if left > 0 && right > 0 && sum < 0 {
+		return math.MaxInt64
+	}
+	if left < 0 && right < 0 && sum >= 0 {
+		return math.MinInt64
+	}
+	return sum
+}
+
+type maxF64Op struct{}
+
+func (maxF64Op) Apply(left, right float64) float64 {
+	if math.IsNaN(left) {
+		return right
+	}
+	if math.IsNaN(right) {
+		return left
+	}
+	return math.Max(left, right)
+}
+
+type minF64Op struct{}
+
+func (minF64Op) Apply(left, right float64) float64 {
+	if math.IsNaN(left) {
+		return right
+	}
+	if math.IsNaN(right) {
+		return left
+	}
+	return math.Min(left, right)
+}
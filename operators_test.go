@@ -0,0 +1,81 @@
+package goadder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLongOperators(t *testing.T) {
+	cases := []struct {
+		name        string
+		op          LongBinaryOperator
+		left, right int64
+		expected    int64
+	}{
+		{"max", MaxOp, 3, 7, 7},
+		{"max", MaxOp, -3, -7, -3},
+		{"min", MinOp, 3, 7, 3},
+		{"min", MinOp, math.MinInt64, 0, math.MinInt64},
+		{"or", OrOp, 0x0f, 0xf0, 0xff},
+		{"and", AndOp, 0x3c, 0x0f, 0x0c},
+		{"xor", XorOp, 0x3c, 0x0f, 0x33},
+		{"saturatingAdd", SaturatingAddOp, 40, 2, 42},
+		{"saturatingAdd", SaturatingAddOp, math.MaxInt64, 1, math.MaxInt64},
+		{"saturatingAdd", SaturatingAddOp, math.MaxInt64 - 5, math.MaxInt64, math.MaxInt64},
+		{"saturatingAdd", SaturatingAddOp, math.MinInt64, -1, math.MinInt64},
+		{"saturatingAdd", SaturatingAddOp, math.MinInt64, math.MaxInt64, -1},
+		{"func", LongBinaryFunc(func(l, r int64) int64 { return l*10 + r }), 4, 2, 42},
+	}
+
+	for _, c := range cases {
+		if actual := c.op.Apply(c.left, c.right); actual != c.expected {
+			t.Errorf("%s(%d, %d) = %d, expected %d", c.name, c.left, c.right, actual, c.expected)
+		}
+	}
+}
+
+func TestFloatOperators(t *testing.T) {
+	nan := math.NaN()
+	negZero := math.Copysign(0, -1)
+
+	cases := []struct {
+		name        string
+		op          FloatBinaryOperator
+		left, right float64
+		expected    float64
+	}{
+		{"max", MaxF64Op, 1.5, -2, 1.5},
+		{"max", MaxF64Op, nan, -2, -2},
+		{"max", MaxF64Op, 3, nan, 3},
+		{"max", MaxF64Op, negZero, 0, 0},
+		{"max", MaxF64Op, math.Inf(-1), 5, 5},
+		{"min", MinF64Op, 1.5, -2, -2},
+		{"min", MinF64Op, nan, -2, -2},
+		{"min", MinF64Op, 3, nan, 3},
+		{"min", MinF64Op, 0, negZero, negZero},
+		{"func", FloatBinaryFunc(func(l, r float64) float64 { return l * r }), 1.5, 4, 6},
+	}
+
+	for _, c := range cases {
+		actual := c.op.Apply(c.left, c.right)
+		if actual != c.expected || math.Signbit(actual) != math.Signbit(c.expected) {
+			t.Errorf("%s(%v, %v) = %v, expected %v", c.name, c.left, c.right, actual, c.expected)
+		}
+	}
+
+	if !math.IsNaN(MaxF64Op.Apply(nan, nan)) || !math.IsNaN(MinF64Op.Apply(nan, nan)) {
+		t.Errorf("NaN handling is wrong")
+	}
+}
+
+func TestOperatorsNoAlloc(t *testing.T) {
+	acc := NewLongAccumulator(MaxOp, math.MinInt64)
+	if allocs := testing.AllocsPerRun(100, func() { acc.Accumulate(1) }); allocs != 0 {
+		t.Errorf("MaxOp allocates: %v", allocs)
+	}
+
+	accF64 := NewDoubleAccumulator(MinF64Op, math.Inf(1))
+	if allocs := testing.AllocsPerRun(100, func() { accF64.Accumulate(1) }); allocs != 0 {
+		t.Errorf("MinF64Op allocates: %v", allocs)
+	}
+}
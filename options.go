@@ -0,0 +1,105 @@
+package goadder
+
+import (
+	"math/bits"
+)
+
+// Option configures an adder. Options about cell table only apply to striped adders
+// such as JDKAdder or JDKF64Adder.
+type Option func(*config)
+
+// WithMaxCells caps the number of cells the adder may grow to under contention.
+// The value is rounded up to the nearest power of two. Non-positive value keeps
+// the package default, which is derived from the number of CPUs.
+//
+// A small cap keeps rarely contended adders tiny, while a large cap lets hot adders
+// spread updates over more cells.
+func WithMaxCells(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.maxCells = nextPowerOfTwo(n)
+		}
+	}
+}
+
+// WithInitialCells sets the size of the cell table allocated upon first contention.
+// The value is rounded up to the nearest power of two and capped by max cells.
+// Non-positive value keeps the default of 2 cells.
+//
+// Sizing the table up front avoids repeated doubling for adders known to be hot.
+func WithInitialCells(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.initialCells = nextPowerOfTwo(n)
+		}
+	}
+}
+
+// WithPaddedCells pads each cell of RandomCellAdder to its own cache lines, the same layout
+// as cells of JDKAdder, so that updates to adjacent cells do not falsely share cache lines.
+// Other adders ignore this option.
+func WithPaddedCells() Option {
+	return func(c *config) {
+		c.paddedCells = true
+	}
+}
+
+type config struct {
+	maxCells         int
+	initialCells     int
+	paddedCells      bool
+	promoteThreshold uint32
+	contentionStats  bool
+	overflowCheck    bool
+	onOverflow       func()
+}
+
+func newConfig(opts []Option) (c config) {
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return
+}
+
+// limit returns maximum number of cells.
+func (c *config) limit() int {
+	if c.maxCells > 0 {
+		return c.maxCells
+	}
+	return maxCells
+}
+
+// newTable creates the initial cell table.
+func (c *config) newTable() cells {
+	n := c.initialCells
+	if n == 0 {
+		n = 2
+	}
+	limit := c.limit()
+	if n > limit {
+		n = limit
+	}
+	return make(cells, n, c.capacity(n<<1))
+}
+
+// capacity caps desired capacity of cell table by max cells.
+func (c *config) capacity(n int) int {
+	if limit := c.limit(); n > limit {
+		return limit
+	}
+	return n
+}
+
+// maxPowerOfTwo is the largest power of two an int holds.
+const maxPowerOfTwo = 1 << (bits.UintSize - 2)
+
+// nextPowerOfTwo rounds n up to the nearest power of two, clamped to maxPowerOfTwo.
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	if n > maxPowerOfTwo {
+		return maxPowerOfTwo
+	}
+	return 1 << bits.Len(uint(n-1))
+}
@@ -0,0 +1,110 @@
+package goadder
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestNextPowerOfTwo(t *testing.T) {
+	for n, expected := range map[int]int{
+		1: 1, 2: 2, 3: 4, 4: 4, 5: 8, 1000: 1024,
+		maxPowerOfTwo: maxPowerOfTwo, maxPowerOfTwo + 1: maxPowerOfTwo, math.MaxInt: maxPowerOfTwo,
+	} {
+		if actual := nextPowerOfTwo(n); actual != expected {
+			t.Errorf("nextPowerOfTwo(%d) = %d, expected %d", n, actual, expected)
+		}
+	}
+}
+
+func TestHugeMaxCells(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithMaxCells(math.MaxInt))
+	adder.Add(10)
+	if adder.Sum() != 10 {
+		t.Errorf("Adder logic is wrong")
+	}
+}
+
+func TestStripedConfig(t *testing.T) {
+	c := newConfig(nil)
+	if c.limit() != maxCells || len(c.newTable()) != 2 || cap(c.newTable()) != 4 {
+		t.Errorf("Default config is wrong")
+	}
+
+	c = newConfig([]Option{WithMaxCells(5), WithInitialCells(0)})
+	if c.limit() != 8 || len(c.newTable()) != 2 {
+		t.Errorf("Config is wrong")
+	}
+
+	c = newConfig([]Option{WithMaxCells(4), WithInitialCells(16)})
+	if c.limit() != 4 || len(c.newTable()) != 4 || cap(c.newTable()) != 4 {
+		t.Errorf("Initial cells must be capped by max cells")
+	}
+}
+
+func TestJDKAdderWithOptions(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithInitialCells(32), WithMaxCells(64))
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+	if as := adder.cells.Load().(cells); len(as) != 32 || cap(as) != 64 {
+		t.Errorf("Initial table is wrong: len=%d cap=%d", len(as), cap(as))
+	}
+
+	testOptionsRaceAdd(t, adder, 1)
+	if as := adder.cells.Load().(cells); len(as) > 64 {
+		t.Errorf("Table exceeds max cells: %d", len(as))
+	}
+}
+
+func TestJDKAdderWithTinyMaxCells(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithMaxCells(1))
+	testOptionsRaceAdd(t, adder, 0)
+	if _as := adder.cells.Load(); _as != nil && len(_as.(cells)) != 1 {
+		t.Errorf("Table exceeds max cells: %d", len(_as.(cells)))
+	}
+}
+
+func TestJDKF64AdderWithOptions(t *testing.T) {
+	adder := NewJDKF64AdderWithOptions(WithInitialCells(8), WithMaxCells(16))
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+	if as := adder.cells.Load().(cells); len(as) != 8 || cap(as) != 16 {
+		t.Errorf("Initial table is wrong: len=%d cap=%d", len(as), cap(as))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Inc()
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if adder.Sum() != float64(delta/10*numRoutine+1) {
+		t.Errorf("Adder logic is wrong")
+	}
+	if as := adder.cells.Load().(cells); len(as) > 16 {
+		t.Errorf("Table exceeds max cells: %d", len(as))
+	}
+}
+
+func testOptionsRaceAdd(t *testing.T, adder LongAdder, initial int64) {
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Inc()
+				adder.Add(2)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if expected := int64(delta/10)*3*int64(numRoutine) + initial; adder.Sum() != expected {
+		t.Errorf("Adder logic is wrong: %d, expected %d", adder.Sum(), expected)
+	}
+}
@@ -0,0 +1,200 @@
+package goadder
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrOverflow is returned by SumChecked when the sum exceeds range of the adder type.
+var ErrOverflow = errors.New("goadder: sum overflows")
+
+// WithOverflowCheck enables overflow detection of integer adders. By default, integer adders wrap around
+// silently. With overflow check, once a sum of the adder is found exceeding range of the type, the adder
+// is marked as overflowed, see Overflowed, and onOverflow is invoked if not nil.
+//
+// Updates which would wrap a cell around are detected inside Add and accumulate, and are spilled into
+// an unbounded slow path instead, so the true sum is always known. The sum is checked by Sum and
+// SumAndReset. Like the sum itself, the check is exact in the absence of concurrent updates; a sum
+// taken while concurrent updates move it back and forth across a bound may report overflow.
+//
+// onOverflow is called synchronously by the routine detecting overflow, possibly more than once,
+// so it should be fast and safe for concurrent use. Float64 adders ignore this option.
+func WithOverflowCheck(onOverflow func()) Option {
+	return func(c *config) {
+		c.overflowCheck = true
+		c.onOverflow = onOverflow
+	}
+}
+
+// overflow tracks overflow state of an integer adder. Updates which would wrap a cell around
+// are added to spill under lock instead, so cells always hold exact partial sums.
+type overflow struct {
+	enabled    bool
+	overflowed int32
+	handler    func()
+
+	spillLock sync.Mutex
+	spill     big.Int
+}
+
+func (o *overflow) init(c *config) {
+	o.enabled = c.overflowCheck
+	o.handler = c.onOverflow
+}
+
+// Overflowed reports whether overflow was detected since creation of adder. Overflow
+// is only detected if adder is created with WithOverflowCheck. The flag is sticky: neither
+// Reset nor Store clears it.
+func (o *overflow) Overflowed() bool {
+	return atomic.LoadInt32(&o.overflowed) != 0
+}
+
+func (o *overflow) report() {
+	atomic.StoreInt32(&o.overflowed, 1)
+	if o.handler != nil {
+		o.handler()
+	}
+}
+
+// resetSpill clears spill. This function is only effective if there are no concurrent updates.
+func (o *overflow) resetSpill() {
+	o.spillLock.Lock()
+	o.spill.SetInt64(0)
+	o.spillLock.Unlock()
+}
+
+// spillInto adds x, which is negative or subtracting by neg, into spill.
+func spillInto[T Number](o *overflow, x T, neg bool) {
+	d := bigOf(x)
+	if neg && d.Sign() > 0 {
+		// unsigned subtraction is encoded as adding two's complement
+		d.Sub(d, new(big.Int).Lsh(big.NewInt(1), 64))
+	}
+
+	o.spillLock.Lock()
+	o.spill.Add(&o.spill, d)
+	o.spillLock.Unlock()
+}
+
+// combine returns sum of cells combined with spill, optionally draining the spill.
+func combine(o *overflow, w wide, drain bool) *big.Int {
+	total := w.big()
+
+	o.spillLock.Lock()
+	total.Add(total, &o.spill)
+	if drain {
+		o.spill.SetInt64(0)
+	}
+	o.spillLock.Unlock()
+
+	return total
+}
+
+// settle combines sum of cells with spill, optionally draining the spill. It returns the combined
+// sum wrapped around into T, and 1 or -1 if the combined sum is above or below range of T, in which
+// case overflow is reported if overflow check is enabled.
+func settle[T Number](o *overflow, w wide, drain bool) (T, int) {
+	total := combine(o, w, drain)
+	v, ok := narrowBig[T](total)
+	if ok {
+		return v, 0
+	}
+	if o.enabled {
+		o.report()
+	}
+	return v, total.Sign()
+}
+
+// saturate returns sum if over is zero. Otherwise, it returns the bound of T which is crossed
+// and ErrOverflow.
+func saturate[T Number](sum T, over int) (T, error) {
+	if over == 0 {
+		return sum, nil
+	}
+	min, max := limits[T]()
+	if over > 0 {
+		return max, ErrOverflow
+	}
+	return min, ErrOverflow
+}
+
+// limits returns minimum and maximum values of integer type T.
+func limits[T Number]() (min, max T) {
+	var zero T
+	if zero-1 > 0 { // unsigned
+		return 0, fromBits[T](^uint64(0))
+	}
+	n := unsafe.Sizeof(zero)*8 - 1
+	return fromBits[T](^uint64(0) << n), fromBits[T](1<<n - 1)
+}
+
+// addUnlessWrapped adds x to value at addr unless the addition would wrap it around,
+// in which case it returns false and leaves the value untouched.
+func addUnlessWrapped[T Number](addr *uint64, x T, neg bool) bool {
+	for {
+		o := atomic.LoadUint64(addr)
+		v := fromBits[T](o)
+		if wrapped(v, v+x, neg) {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(addr, o, toBits(v+x)) {
+			return true
+		}
+	}
+}
+
+// wrapped reports whether updating old to new, by adding (neg=false) or subtracting (neg=true)
+// a value, wraps around.
+func wrapped[T Number](old, new T, neg bool) bool {
+	if isFloat[T]() {
+		return false
+	}
+	if neg {
+		return new > old
+	}
+	return new < old
+}
+
+func bigOf[T Number](v T) *big.Int {
+	if v < 0 {
+		return big.NewInt(int64(v))
+	}
+	return new(big.Int).SetUint64(uint64(v))
+}
+
+// narrowBig converts b to T, wrapping around if b is out of range of T. The second value
+// reports whether b is in range.
+func narrowBig[T Number](b *big.Int) (T, bool) {
+	var lo big.Int
+	lo.And(b, new(big.Int).SetUint64(^uint64(0))) // two's complement low bits
+	v := fromBits[T](lo.Uint64())
+	return v, bigOf(v).Cmp(b) == 0
+}
+
+// wide is a 128-bit two's complement integer, used to sum cells without wrapping around.
+type wide struct {
+	lo, hi uint64
+}
+
+func addWide[T Number](w *wide, v T) {
+	var carry uint64
+	w.lo, carry = bits.Add64(w.lo, toBits(v), 0)
+	w.hi += carry
+	if v < 0 {
+		w.hi-- // sign extension
+	}
+}
+
+func (w wide) big() *big.Int {
+	b := new(big.Int).SetUint64(w.hi)
+	b.Lsh(b, 64)
+	b.Or(b, new(big.Int).SetUint64(w.lo))
+	if int64(w.hi) < 0 {
+		b.Sub(b, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	return b
+}
@@ -0,0 +1,145 @@
+package goadder
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+var longAdderTypes = []Type{JDKAdderType, RandomCellAdderType, AtomicAdderType, MutexAdderType, PerPAdderType, AdaptiveAdderType}
+
+func TestCheckedLongAdder(t *testing.T) {
+	for _, ty := range longAdderTypes {
+		var calls int
+		adder := NewCheckedLongAdder(ty, func() { calls++ })
+
+		adder.Add(math.MaxInt64)
+		if v, err := adder.SumChecked(); err != nil || v != math.MaxInt64 {
+			t.Errorf("Adder(%d) logic is wrong", ty)
+		}
+
+		adder.Add(10)
+		if v, err := adder.SumChecked(); err != ErrOverflow || v != math.MaxInt64 || !adder.Overflowed() || calls == 0 {
+			t.Errorf("Adder(%d) must saturate at max", ty)
+		}
+		if adder.Sum() != math.MinInt64+9 {
+			t.Errorf("Adder(%d) must wrap around on Sum", ty)
+		}
+
+		// spilled additions are kept, the sum comes back into range
+		adder.Add(-20)
+		if v, err := adder.SumChecked(); err != nil || v != math.MaxInt64-10 {
+			t.Errorf("Adder(%d) logic is wrong", ty)
+		}
+
+		adder.Store(math.MinInt64)
+		adder.Dec()
+		adder.Add(math.MinInt64)
+		if v, err := adder.SumChecked(); err != ErrOverflow || v != math.MinInt64 {
+			t.Errorf("Adder(%d) must saturate at min", ty)
+		}
+		if adder.SumAndReset() != -1 {
+			t.Errorf("Adder(%d) must wrap around on SumAndReset", ty)
+		}
+		if v, err := adder.SumChecked(); err != nil || v != 0 {
+			t.Errorf("Adder(%d) must drain spilled additions", ty)
+		}
+	}
+}
+
+func TestSumCheckedWithoutOverflowCheck(t *testing.T) {
+	for _, ty := range longAdderTypes {
+		adder := NewLongAdder(ty).(CheckedLongAdder)
+		adder.Add(math.MaxInt64)
+		adder.Inc()
+		if adder.Sum() != math.MinInt64 {
+			t.Errorf("Adder(%d) must wrap around", ty)
+		}
+		adder.SumChecked()
+		if adder.Overflowed() {
+			t.Errorf("Adder(%d) must not report overflow without check", ty)
+		}
+	}
+
+	// cells of striped adders do not wrap around, only their sum does
+	adder := NewJDKAdder()
+	adder.Add(math.MaxInt64)
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+	if v, err := adder.SumChecked(); err != ErrOverflow || v != math.MaxInt64 {
+		t.Errorf("SumChecked must detect aggregated overflow")
+	}
+	if adder.Overflowed() {
+		t.Errorf("SumChecked must not report overflow without check")
+	}
+}
+
+func TestCheckedLongAdderRace(t *testing.T) {
+	for _, ty := range longAdderTypes {
+		adder := NewCheckedLongAdder(ty, nil)
+		adder.Store(math.MaxInt64 - int64(numRoutine))
+
+		var wg sync.WaitGroup
+		for i := 0; i < numRoutine; i++ {
+			wg.Add(1)
+			go func() {
+				for j := 0; j < delta/10; j++ {
+					adder.Add(math.MaxInt64)
+					adder.Inc()
+					adder.Add(-math.MaxInt64)
+					adder.Dec()
+				}
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+
+		if v, err := adder.SumChecked(); err != nil || v != math.MaxInt64-int64(numRoutine) {
+			t.Errorf("Adder(%d) logic is wrong", ty)
+		}
+	}
+}
+
+func TestJDKF64AdderSumChecked(t *testing.T) {
+	adder := NewJDKF64Adder()
+	adder.Add(math.MaxFloat64)
+	if _, err := adder.SumChecked(); err != nil {
+		t.Errorf("SumChecked logic is wrong")
+	}
+	adder.Add(math.MaxFloat64)
+	if v, err := adder.SumChecked(); err != ErrOverflow || !math.IsInf(v, 1) {
+		t.Errorf("SumChecked must report infinite sum")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	if min, max := limits[int64](); min != math.MinInt64 || max != math.MaxInt64 {
+		t.Errorf("Limits of int64 are wrong")
+	}
+	if min, max := limits[int32](); min != math.MinInt32 || max != math.MaxInt32 {
+		t.Errorf("Limits of int32 are wrong")
+	}
+	if min, max := limits[uint64](); min != 0 || max != math.MaxUint64 {
+		t.Errorf("Limits of uint64 are wrong")
+	}
+}
+
+func TestCheckedJDKAdderCompact(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithOverflowCheck(nil))
+	adder.Add(math.MaxInt64)
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+
+	// folding the cell into base must spill instead of wrapping base around
+	adder.Compact()
+	if v, err := adder.SumChecked(); err != ErrOverflow || v != math.MaxInt64 || !adder.Overflowed() {
+		t.Errorf("Compact must keep overflow checked")
+	}
+	if adder.Sum() != math.MinInt64 {
+		t.Errorf("Compact must keep the sum")
+	}
+
+	adder.Dec()
+	adder.Compact()
+	if v, err := adder.SumChecked(); err != nil || v != math.MaxInt64 {
+		t.Errorf("Compact logic is wrong")
+	}
+}
@@ -0,0 +1,147 @@
+package goadder
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// PerPAdder shards updates by the processor (P) running the invoker, with one padded Cell per P.
+//
+// RandomCellAdder picks a random Cell on every update and JDKAdder rehashes upon collision, so a routine
+// keeps bouncing between Cells. PerPAdder instead pins the routine to its P for the duration of an
+// update, hence a Cell is mostly updated by one P at a time and stays in cache of that processor.
+// Contention on Cells is rare, it happens only when a routine is moved to another P in the middle of
+// an update.
+//
+// The table of Cells is sized by GOMAXPROCS. If GOMAXPROCS is raised later, the table grows upon the
+// first update from a new P, keeping existing Cells, so no update is lost. Cells are never removed.
+//
+// PerPAdder relies on runtime.procPin via go:linkname. Building with tag purego drops this dependency,
+// Cells are then picked at random among GOMAXPROCS ones, GOMAXPROCS being read again about every 1024 updates.
+//
+// PerPAdder is high performance, non-blocking on update and safe for concurrent use.
+type PerPAdder struct {
+	cells atomic.Value // []*cell[int64], indexed by id of P
+	lock  sync.Mutex   // guards growing of cells
+	overflow
+}
+
+// NewPerPAdder create new PerPAdder. Options about cell table are ignored.
+func NewPerPAdder(opts ...Option) *PerPAdder {
+	p := &PerPAdder{}
+	c := newConfig(opts)
+	p.overflow.init(&c)
+	p.cells.Store(growPerPCells(nil, runtime.GOMAXPROCS(0)))
+	return p
+}
+
+// growPerPCells returns a table of n cells, reusing cells of as.
+func growPerPCells(as []*cell[int64], n int) []*cell[int64] {
+	rs := make([]*cell[int64], n)
+	copy(rs, as)
+	for i := len(as); i < n; i++ {
+		rs[i] = &cell[int64]{}
+	}
+	return rs
+}
+
+// Add the given value
+func (p *PerPAdder) Add(x int64) {
+	for {
+		pid := procPin()
+		if as := p.cells.Load().([]*cell[int64]); pid < len(as) {
+			if c := &as[pid].val; !p.overflow.enabled {
+				atomic.AddUint64(c, uint64(x))
+			} else if !addUnlessWrapped(c, x, x < 0) {
+				procUnpin()
+				spillInto(&p.overflow, x, x < 0)
+				return
+			}
+			procUnpin()
+			return
+		}
+		procUnpin()
+
+		// GOMAXPROCS was raised
+		p.lock.Lock()
+		if as := p.cells.Load().([]*cell[int64]); pid >= len(as) {
+			p.cells.Store(growPerPCells(as, pid+1))
+		}
+		p.lock.Unlock()
+	}
+}
+
+// Inc by 1
+func (p *PerPAdder) Inc() {
+	p.Add(1)
+}
+
+// Dec by 1
+func (p *PerPAdder) Dec() {
+	p.Add(-1)
+}
+
+// Sum return the current sum. The returned value is NOT an
+// atomic snapshot because of concurrent update.
+func (p *PerPAdder) Sum() int64 {
+	sum, _ := p.fold((*cell[int64]).load, false, p.overflow.enabled)
+	return sum
+}
+
+// String returns the current sum as JSON.
+func (p *PerPAdder) String() string {
+	return formatJSON(p.Sum())
+}
+
+// SumChecked return the current sum like Sum, but never wraps around silently. If the sum exceeds
+// range of int64, it returns the nearest bound of int64, i.e. saturating sum, along with ErrOverflow.
+//
+// Cells may wrap around unless the adder is created with WithOverflowCheck,
+// thus an exact result requires that option.
+func (p *PerPAdder) SumChecked() (int64, error) {
+	return saturate(p.fold((*cell[int64]).load, false, true))
+}
+
+// Reset variables maintaining the sum to zero. Updates concurrent with this method are either
+// discarded or retained after the reset, never partially applied.
+func (p *PerPAdder) Reset() {
+	p.SumAndReset()
+}
+
+// SumAndReset equivalent in effect to sum followed by reset. Each Cell is atomically
+// swapped to zero, so it is safe to drain a live adder: every update concurrent with
+// this method is counted in exactly one of the returned value or subsequent sums.
+func (p *PerPAdder) SumAndReset() int64 {
+	sum, _ := p.fold(func(c *cell[int64]) int64 { return c.swap(0) }, true, p.overflow.enabled)
+	return sum
+}
+
+// Store value. This function is only effective if there are no concurrent updates.
+func (p *PerPAdder) Store(v int64) {
+	if p.overflow.enabled {
+		p.overflow.resetSpill()
+	}
+	as := p.cells.Load().([]*cell[int64])
+	for i := 1; i < len(as); i++ {
+		as[i].store(0)
+	}
+	as[0].store(v)
+}
+
+// fold sums values got from each cell. If check is set, it also counts spilled
+// additions and reports whether the sum exceeds range of int64, see settle.
+func (p *PerPAdder) fold(get func(*cell[int64]) int64, drain, check bool) (sum int64, over int) {
+	var w wide
+	for _, c := range p.cells.Load().([]*cell[int64]) {
+		v := get(c)
+		if sum += v; check {
+			addWide(&w, v)
+		}
+	}
+
+	if check {
+		return settle[int64](&p.overflow, w, drain)
+	}
+	return
+}
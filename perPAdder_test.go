@@ -0,0 +1,88 @@
+package goadder
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPerPAdderNotRaceInc(t *testing.T) {
+	testAdderNotRaceInc(t, PerPAdderType)
+}
+
+func TestPerPAdderRaceInc(t *testing.T) {
+	testAdderRaceInc(t, PerPAdderType)
+}
+
+func TestPerPAdderNotRaceDec(t *testing.T) {
+	testAdderNotRaceDec(t, PerPAdderType)
+}
+
+func TestPerPAdderRaceDec(t *testing.T) {
+	testAdderRaceDec(t, PerPAdderType)
+}
+
+func TestPerPAdderNotRaceAdd(t *testing.T) {
+	testAdderNotRaceAdd(t, PerPAdderType)
+}
+
+func TestPerPAdderRaceAdd(t *testing.T) {
+	testAdderRaceAdd(t, PerPAdderType)
+}
+
+func TestPerPAdderRaceDrain(t *testing.T) {
+	testAdderRaceDrain(t, PerPAdderType)
+}
+
+func TestPerPAdderGOMAXPROCS(t *testing.T) {
+	procs := runtime.GOMAXPROCS(1)
+	defer runtime.GOMAXPROCS(procs)
+
+	adder := NewPerPAdder()
+	if n := len(adder.cells.Load().([]*cell[int64])); n != 1 {
+		t.Errorf("PerPAdder must have 1 cell, got %d", n)
+	}
+	adder.Add(5)
+
+	// grow and shrink while updating
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < delta/10; j++ {
+				adder.Inc()
+			}
+			wg.Done()
+		}(i)
+	}
+	for _, n := range []int{4, 2, 8} {
+		runtime.GOMAXPROCS(n)
+		runtime.Gosched()
+	}
+	wg.Wait()
+
+	// routines are scheduled on other Ps eventually, growing cells
+	expected := 5 + int64(delta/10*numRoutine)
+	for deadline := time.Now().Add(10 * time.Second); len(adder.cells.Load().([]*cell[int64])) <= 1 && time.Now().Before(deadline); {
+		for i := 0; i < numRoutine; i++ {
+			wg.Add(1)
+			go func() {
+				for j := 0; j < 1000; j++ {
+					adder.Inc()
+					runtime.Gosched()
+				}
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+		expected += int64(1000 * numRoutine)
+	}
+
+	if n := len(adder.cells.Load().([]*cell[int64])); n <= 1 || n > 8 {
+		t.Errorf("PerPAdder must grow cells upon GOMAXPROCS, got %d", n)
+	}
+	if adder.Sum() != expected {
+		t.Errorf("PerPAdder logic is wrong")
+	}
+}
@@ -1,4 +1,4 @@
-// Package goadder contains a collection of thread-safe, concurrent data structures for reading and writing numeric int64-counter,
+// Package goadder contains a collection of thread-safe, concurrent data structures for reading and writing numeric counters,
 // inspired by OpenJDK9 LongAdder.
 //
 // Beside JDKAdder, ported version of OpenJDK9 LongAdder, package also provides other alternatives for various use cases.
@@ -20,62 +20,116 @@ const (
 	JDKF64AdderType
 	// AtomicF64AdderType is type for atomic-based float64 adder
 	AtomicF64AdderType
+	// KahanF64AdderType is type for compensated JDK-based DoubleAdder
+	KahanF64AdderType
+	// PerPAdderType is type for PerPAdder
+	PerPAdderType
+	// AdaptiveAdderType is type for AdaptiveAdder
+	AdaptiveAdderType
 )
 
-// LongAdder interface
-type LongAdder interface {
-	Add(x int64)
+// Number is the set of numeric types supported by generic adders.
+type Number interface {
+	int64 | uint64 | int32 | float64
+}
+
+// Integer is the set of integer types supported by generic adders.
+type Integer interface {
+	int64 | uint64 | int32
+}
+
+// Adder interface
+type Adder[T Number] interface {
+	Add(x T)
 	Inc()
 	Dec()
-	Sum() int64
+	Sum() T
 	Reset()
-	SumAndReset() int64
-	Store(v int64)
+	SumAndReset() T
+	Store(v T)
 }
 
+// LongAdder interface
+type LongAdder = Adder[int64]
+
 // Float64Adder interface
-type Float64Adder interface {
-	Add(x float64)
-	Inc()
-	Dec()
-	Sum() float64
-	Reset()
-	SumAndReset() float64
-	Store(v float64)
+type Float64Adder = Adder[float64]
+
+// CheckedAdder is an integer adder supporting overflow check, see WithOverflowCheck.
+type CheckedAdder[T Integer] interface {
+	Adder[T]
+	SumChecked() (T, error)
+	Overflowed() bool
 }
 
-// NewLongAdder create new long adder upon type
-func NewLongAdder(t Type) LongAdder {
+// CheckedLongAdder interface
+type CheckedLongAdder = CheckedAdder[int64]
+
+// NewAdder create new adder of numeric type T upon type with given options. Types other than int64,
+// uint64 and float64 are always backed by StripedAdder.
+func NewAdder[T Number](t Type, opts ...Option) Adder[T] {
+	var zero T
+	switch any(zero).(type) {
+	case int64:
+		return any(NewLongAdder(t, opts...)).(Adder[T])
+	case uint64:
+		return any(NewUint64Adder(t, opts...)).(Adder[T])
+	case float64:
+		return any(NewFloat64Adder(t, opts...)).(Adder[T])
+	default:
+		return NewStripedAdder[T](opts...)
+	}
+}
+
+// NewLongAdder create new long adder upon type with given options.
+// Every long adder returned is a CheckedLongAdder.
+func NewLongAdder(t Type, opts ...Option) LongAdder {
 	switch t {
 	case MutexAdderType:
-		return NewMutexAdder()
+		return NewMutexAdder(opts...)
 	case AtomicAdderType:
-		return NewAtomicAdder()
+		return NewAtomicAdder(opts...)
 	case RandomCellAdderType:
-		return NewRandomCellAdder()
+		return NewRandomCellAdder(opts...)
+	case PerPAdderType:
+		return NewPerPAdder(opts...)
+	case AdaptiveAdderType:
+		return NewAdaptiveAdder(opts...)
 	default:
-		return NewJDKAdder()
+		return NewJDKAdderWithOptions(opts...)
 	}
 }
 
-// NewFloat64Adder create new float64 adder upon type
-func NewFloat64Adder(t Type) Float64Adder {
+// NewCheckedLongAdder create new long adder upon type, with overflow check enabled.
+// onOverflow is optional, see WithOverflowCheck.
+func NewCheckedLongAdder(t Type, onOverflow func(), opts ...Option) CheckedLongAdder {
+	opts = append(opts[:len(opts):len(opts)], WithOverflowCheck(onOverflow))
+	return NewLongAdder(t, opts...).(CheckedLongAdder)
+}
+
+// NewFloat64Adder create new float64 adder upon type with given options.
+// Options are ignored by AtomicF64Adder.
+func NewFloat64Adder(t Type, opts ...Option) Float64Adder {
 	switch t {
 	case AtomicF64AdderType:
 		return NewAtomicF64Adder()
+	case KahanF64AdderType:
+		return NewKahanF64Adder(opts...)
 	default:
-		return NewJDKF64Adder()
+		return NewJDKF64AdderWithOptions(opts...)
 	}
 }
 
+// BinaryOperator represents an operation upon two T-valued operands and producing a
+// T-valued result
+type BinaryOperator[T Number] interface {
+	Apply(left, right T) T
+}
+
 // LongBinaryOperator represents an operation upon two int64-valued operands and producing an
 // int64-valued result
-type LongBinaryOperator interface {
-	Apply(left, right int64) int64
-}
+type LongBinaryOperator = BinaryOperator[int64]
 
 // FloatBinaryOperator represents an operation upon two float64-valued operands and producing an
 // float64-valued result
-type FloatBinaryOperator interface {
-	Apply(left, right float64) float64
-}
+type FloatBinaryOperator = BinaryOperator[float64]
@@ -7,12 +7,15 @@ import (
 
 var atomicF64Adder1 = NewFloat64Adder(AtomicF64AdderType)
 var jdkF64Adder1 = NewFloat64Adder(JDKF64AdderType)
+var kahanF64Adder1 = NewFloat64Adder(KahanF64AdderType)
 
 var atomicF64Adder2 = NewFloat64Adder(AtomicF64AdderType)
 var jdkF64Adder2 = NewFloat64Adder(JDKF64AdderType)
+var kahanF64Adder2 = NewFloat64Adder(KahanF64AdderType)
 
 var atomicF64Adder3 = NewFloat64Adder(AtomicF64AdderType)
 var jdkF64Adder3 = NewFloat64Adder(JDKF64AdderType)
+var kahanF64Adder3 = NewFloat64Adder(KahanF64AdderType)
 
 func BenchmarkAtomicF64AdderSingleRoutine(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -26,6 +29,12 @@ func BenchmarkJDKF64AdderSingleRoutine(b *testing.B) {
 	}
 }
 
+func BenchmarkKahanF64AdderSingleRoutine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchF64AdderSingleRoutine(kahanF64Adder1)
+	}
+}
+
 func BenchmarkAtomicF64AdderMultiRoutine(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		benchF64AdderMultiRoutine(atomicF64Adder2)
@@ -38,6 +47,12 @@ func BenchmarkJDKF64AdderMultiRoutine(b *testing.B) {
 	}
 }
 
+func BenchmarkKahanF64AdderMultiRoutine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchF64AdderMultiRoutine(kahanF64Adder2)
+	}
+}
+
 func BenchmarkAtomicF64AdderMultiRoutineMix(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		benchF64AdderMultiRoutineMix(atomicF64Adder3)
@@ -50,6 +65,12 @@ func BenchmarkJDKF64AdderMultiRoutineMix(b *testing.B) {
 	}
 }
 
+func BenchmarkKahanF64AdderMultiRoutineMix(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchF64AdderMultiRoutineMix(kahanF64Adder3)
+	}
+}
+
 func benchF64AdderSingleRoutine(adder Float64Adder) {
 	for i := 0; i < benchDeltaSingleRoute; i++ {
 		adder.Add(1.1)
@@ -14,16 +14,19 @@ var atomicAdder1 = NewLongAdder(AtomicAdderType)
 var mutexAdder1 = NewLongAdder(MutexAdderType)
 var jdkAdder1 = NewLongAdder(JDKAdderType)
 var randomCellAdder1 = NewLongAdder(RandomCellAdderType)
+var perPAdder1 = NewLongAdder(PerPAdderType)
 
 var atomicAdder2 = NewLongAdder(AtomicAdderType)
 var mutexAdder2 = NewLongAdder(MutexAdderType)
 var jdkAdder2 = NewLongAdder(JDKAdderType)
 var randomCellAdder2 = NewLongAdder(RandomCellAdderType)
+var perPAdder2 = NewLongAdder(PerPAdderType)
 
 var atomicAdder3 = NewLongAdder(AtomicAdderType)
 var mutexAdder3 = NewLongAdder(MutexAdderType)
 var jdkAdder3 = NewLongAdder(JDKAdderType)
 var randomCellAdder3 = NewLongAdder(RandomCellAdderType)
+var perPAdder3 = NewLongAdder(PerPAdderType)
 
 func init() {
 	// set max procs to thread contention
@@ -54,6 +57,12 @@ func BenchmarkJDKAdderSingleRoutine(b *testing.B) {
 	}
 }
 
+func BenchmarkPerPAdderSingleRoutine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchAdderSingleRoutine(perPAdder1)
+	}
+}
+
 func BenchmarkMutexAdderMultiRoutine(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		benchAdderMultiRoutine(mutexAdder2)
@@ -78,6 +87,12 @@ func BenchmarkJDKAdderMultiRoutine(b *testing.B) {
 	}
 }
 
+func BenchmarkPerPAdderMultiRoutine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchAdderMultiRoutine(perPAdder2)
+	}
+}
+
 func BenchmarkMutexAdderMultiRoutineMix(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		benchAdderMultiRoutineMix(mutexAdder3)
@@ -101,6 +116,68 @@ func BenchmarkJDKAdderMultiRoutineMix(b *testing.B) {
 	}
 }
 
+func BenchmarkPerPAdderMultiRoutineMix(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchAdderMultiRoutineMix(perPAdder3)
+	}
+}
+
+func BenchmarkRandomCellAdderPackedMultiRoutine(b *testing.B) {
+	adder, _ := NewRandomCellAdderWithSize(16)
+	for i := 0; i < b.N; i++ {
+		benchAdderMultiRoutine(adder)
+	}
+}
+
+func BenchmarkRandomCellAdderPaddedMultiRoutine(b *testing.B) {
+	adder, _ := NewRandomCellAdderWithSize(16, WithPaddedCells())
+	for i := 0; i < b.N; i++ {
+		benchAdderMultiRoutine(adder)
+	}
+}
+
+func BenchmarkJDKAdderManyMultiRoutine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchManyAdders(false)
+	}
+}
+
+func BenchmarkJDKAdderBatchMultiRoutine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchManyAdders(true)
+	}
+}
+
+var manyAdders = func() (adders []LongAdder) {
+	for i := 0; i < 20; i++ {
+		adders = append(adders, NewLongAdder(JDKAdderType))
+	}
+	return
+}()
+
+// benchManyAdders updates 20 adders per iteration, either one by one or within a batch.
+func benchManyAdders(batched bool) {
+	var wg sync.WaitGroup
+	for i := 0; i < benchNumRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			batch := NewBatch()
+			for j := 0; j < benchDelta/len(manyAdders); j++ {
+				for _, adder := range manyAdders {
+					if batched {
+						batch.Add(adder, 1)
+					} else {
+						adder.Add(1)
+					}
+				}
+				batch.Commit()
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
 func benchAdderSingleRoutine(adder LongAdder) {
 	for i := 0; i < benchDeltaSingleRoute; i++ {
 		adder.Add(1)
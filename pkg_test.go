@@ -239,3 +239,89 @@ func testF64AdderRaceAdd(t *testing.T, ty Type) {
 		t.Errorf("Adder(%d) logic is wrong", ty)
 	}
 }
+
+var drainDelta = 200000
+
+// testAdderRaceDrain checks that draining a live adder never loses nor double-counts updates:
+// the sum of all drained intervals must match the total added by writers.
+func testAdderRaceDrain(t *testing.T, ty Type) {
+	adder := NewLongAdder(ty)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < drainDelta; j++ {
+				adder.Add(int64(j & 7))
+			}
+			wg.Done()
+		}()
+	}
+
+	done := make(chan struct{})
+	drained := make(chan int64)
+	go func() {
+		var total int64
+		for {
+			select {
+			case <-done:
+				drained <- total + adder.SumAndReset()
+				return
+			default:
+				total += adder.SumAndReset()
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	expected := int64(drainDelta/8) * 28 * int64(numRoutine)
+	if actual := <-drained; actual != expected {
+		t.Errorf("Adder(%d) drained %d, expected %d", ty, actual, expected)
+	}
+	if adder.Sum() != 0 {
+		t.Errorf("Adder(%d) logic is wrong", ty)
+	}
+}
+
+func testF64AdderRaceDrain(t *testing.T, ty Type) {
+	adder := NewFloat64Adder(ty)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < drainDelta; j++ {
+				adder.Add(float64(j & 7))
+			}
+			wg.Done()
+		}()
+	}
+
+	done := make(chan struct{})
+	drained := make(chan float64)
+	go func() {
+		var total float64
+		for {
+			select {
+			case <-done:
+				drained <- total + adder.SumAndReset()
+				return
+			default:
+				total += adder.SumAndReset()
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	expected := float64(drainDelta/8) * 28 * float64(numRoutine)
+	if actual := <-drained; actual != expected {
+		t.Errorf("Adder(%d) drained %v, expected %v", ty, actual, expected)
+	}
+	if adder.Sum() != 0 {
+		t.Errorf("Adder(%d) logic is wrong", ty)
+	}
+}
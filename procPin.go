@@ -0,0 +1,16 @@
+//go:build !purego
+
+package goadder
+
+import (
+	_ "unsafe" // for go:linkname
+)
+
+// procPin pins current routine to its P, disabling preemption, and returns id of the P.
+// It must be paired with procUnpin.
+//
+//go:linkname procPin runtime.procPin
+func procPin() int
+
+//go:linkname procUnpin runtime.procUnpin
+func procUnpin()
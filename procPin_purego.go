@@ -0,0 +1,25 @@
+//go:build purego
+
+package goadder
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// fallbackProcs is the number of shards used without access to runtime internals. It follows
+// GOMAXPROCS lazily, since reading it takes a global lock.
+var fallbackProcs = int32(runtime.GOMAXPROCS(0))
+
+// procPin is the pure Go fallback, which picks a random shard instead of id of current P.
+// So PerPAdder behaves like RandomCellAdder with padded cells. About once every 1024 calls,
+// GOMAXPROCS is read again, so that raising it spreads updates over more shards.
+func procPin() int {
+	r := getRandomInt()
+	if r&1023 == 0 {
+		atomic.StoreInt32(&fallbackProcs, int32(runtime.GOMAXPROCS(0)))
+	}
+	return (r >> 10) % int(atomic.LoadInt32(&fallbackProcs))
+}
+
+func procUnpin() {}
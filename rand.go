@@ -11,3 +11,11 @@ const (
 func getRandomInt() int {
 	return int(fastrand.Uint32() & limit)
 }
+
+// orRandom returns probe, or a random one if probe is zero.
+func orRandom(probe int) int {
+	if probe == 0 {
+		return getRandomInt()
+	}
+	return probe
+}
@@ -1,14 +1,17 @@
 package goadder
 
 import (
+	"errors"
 	"sync/atomic"
 )
 
 const (
-	randomCellSize      = 1 << 7 // 128
-	randomCellSizeMinus = randomCellSize - 1
+	randomCellSize = 1 << 7 // 128
 )
 
+// ErrCellSize is returned when number of cells is not a positive power of two.
+var ErrCellSize = errors.New("goadder: number of cells must be a positive power of two")
+
 // RandomCellAdder takes idea from JDKAdder by preallocating a fixed number of Cells. Unlike JDKAdder, in each update,
 // RandomCellAdder assign a random-fixed Cell to invoker instead of retry/reassign Cell when contention.
 //
@@ -16,20 +19,37 @@ const (
 // but slower in case of single routine (no race).
 //
 // RandomCellAdder consume ~1KB for storing cells, which is often larger than JDKAdder which number of cells is dynamic.
+// Cells are packed by default, so adjacent cells share cache lines. With WithPaddedCells, each cell occupies
+// its own cache line like cells of JDKAdder, at the expense of 128 bytes per cell.
 type RandomCellAdder struct {
-	cells []int64
+	randomCells[int64]
+}
+
+// NewRandomCellAdder create new RandomCellAdder of 128 cells.
+func NewRandomCellAdder(opts ...Option) *RandomCellAdder {
+	r := &RandomCellAdder{}
+	r.init(randomCellSize, opts)
+	return r
 }
 
-// NewRandomCellAdder create new RandomCellAdder
-func NewRandomCellAdder() *RandomCellAdder {
-	return &RandomCellAdder{
-		cells: make([]int64, randomCellSize),
+// NewRandomCellAdderWithSize create new RandomCellAdder of n cells. It returns ErrCellSize
+// if n is not a positive power of two.
+func NewRandomCellAdderWithSize(n int, opts ...Option) (*RandomCellAdder, error) {
+	if n <= 0 || n&(n-1) != 0 {
+		return nil, ErrCellSize
 	}
+	r := &RandomCellAdder{}
+	r.init(n, opts)
+	return r, nil
 }
 
 // Add the given value
 func (r *RandomCellAdder) Add(x int64) {
-	atomic.AddInt64(&r.cells[getRandomInt()&randomCellSizeMinus], x)
+	if r.cells == nil || r.overflow.enabled { // padded or checked
+		r.add(x, x < 0, getRandomInt())
+		return
+	}
+	atomic.AddUint64(&r.cells[getRandomInt()&r.mask], uint64(x))
 }
 
 // Inc by 1
@@ -48,39 +68,145 @@ func (r *RandomCellAdder) Dec() {
 // occur while the sum is being calculated might not be
 // incorporated.
 func (r *RandomCellAdder) Sum() (sum int64) {
+	if r.cells == nil || r.overflow.enabled { // padded or checked
+		return r.randomCells.Sum()
+	}
 	for i := range r.cells {
-		sum += atomic.LoadInt64(&r.cells[i])
+		sum += int64(atomic.LoadUint64(&r.cells[i]))
 	}
 	return
 }
 
+// randomCells holds integers of type T as two's complement uint64 cells, either packed or padded.
+type randomCells[T Integer] struct {
+	cells  []uint64
+	padded []cell[T]
+	mask   int
+	overflow
+}
+
+func (r *randomCells[T]) init(n int, opts []Option) {
+	c := newConfig(opts)
+	if c.paddedCells {
+		r.padded = make([]cell[T], n)
+	} else {
+		r.cells = make([]uint64, n)
+	}
+	r.mask = n - 1
+	r.overflow.init(&c)
+}
+
+// cell returns address of value of i-th cell.
+func (r *randomCells[T]) cell(i int) *uint64 {
+	if r.padded != nil {
+		return &r.padded[i].val
+	}
+	return &r.cells[i]
+}
+
+// Add the given value
+func (r *randomCells[T]) Add(x T) {
+	r.add(x, x < 0, getRandomInt())
+}
+
+// Inc by 1
+func (r *randomCells[T]) Inc() {
+	r.add(1, false, getRandomInt())
+}
+
+// Dec by 1
+func (r *randomCells[T]) Dec() {
+	var one T = 1
+	r.add(-one, true, getRandomInt())
+}
+
+// addProbe adds x using the given probe instead of a random one.
+func (r *randomCells[T]) addProbe(x T, probe int) {
+	r.add(x, x < 0, probe)
+}
+
+func (r *randomCells[T]) add(x T, neg bool, probe int) {
+	c := r.cell(probe & r.mask)
+	if r.overflow.enabled {
+		r.addChecked(c, x, neg)
+		return
+	}
+	atomic.AddUint64(c, toBits(x))
+}
+
+// addChecked adds x to cell c unless it would wrap c around, in which case x is spilled.
+// It is kept out of line, so that unchecked Add inlines.
+//
+//go:noinline
+func (r *randomCells[T]) addChecked(c *uint64, x T, neg bool) {
+	if !addUnlessWrapped(c, x, neg) {
+		spillInto(&r.overflow, x, neg)
+	}
+}
+
+// Sum return the current sum. The returned value is NOT an
+// atomic snapshot; invocation in the absence of concurrent
+// updates returns an accurate result, but concurrent updates that
+// occur while the sum is being calculated might not be
+// incorporated.
+func (r *randomCells[T]) Sum() T {
+	sum, _ := r.fold(atomic.LoadUint64, false, r.overflow.enabled)
+	return sum
+}
+
+// String returns the current sum as JSON.
+func (r *randomCells[T]) String() string {
+	return formatJSON(r.Sum())
+}
+
+// SumChecked return the current sum like Sum, but never wraps around silently. If the sum exceeds
+// range of T, it returns the nearest bound of T, i.e. saturating sum, along with ErrOverflow.
+//
+// Cells may wrap around unless the adder is created with WithOverflowCheck,
+// thus an exact result requires that option.
+func (r *randomCells[T]) SumChecked() (T, error) {
+	return saturate(r.fold(atomic.LoadUint64, false, true))
+}
+
 // Reset variables maintaining the sum to zero. This method may be a useful alternative
 // to creating a new adder, but is only effective if there are no concurrent updates.
 // Because this method is intrinsically racy
-func (r *RandomCellAdder) Reset() {
-	for i := range r.cells {
-		atomic.StoreInt64(&r.cells[i], 0)
-	}
+func (r *randomCells[T]) Reset() {
+	r.Store(0)
 }
 
-// SumAndReset equivalent in effect to sum followed by reset.
-// This method may apply for example during quiescent
-// points between multithreaded computations. If there are
-// updates concurrent with this method, the returned value is
-// guaranteed to be the final value occurring before
-// the reset.
-func (r *RandomCellAdder) SumAndReset() (sum int64) {
-	for i := range r.cells {
-		sum += atomic.LoadInt64(&r.cells[i])
-		atomic.StoreInt64(&r.cells[i], 0)
-	}
-	return
+// SumAndReset equivalent in effect to sum followed by reset. Each cell is atomically
+// swapped to zero, so it is safe to drain a live adder: every update concurrent with
+// this method is counted in exactly one of the returned value or subsequent sums.
+func (r *randomCells[T]) SumAndReset() T {
+	sum, _ := r.fold(func(c *uint64) uint64 { return atomic.SwapUint64(c, 0) }, true, r.overflow.enabled)
+	return sum
 }
 
 // Store value. This function is only effective if there are no concurrent updates.
-func (r *RandomCellAdder) Store(v int64) {
-	atomic.StoreInt64(&r.cells[0], v)
-	for i := 1; i < randomCellSize; i++ {
-		atomic.StoreInt64(&r.cells[i], 0)
+func (r *randomCells[T]) Store(v T) {
+	if r.overflow.enabled {
+		r.overflow.resetSpill()
+	}
+	atomic.StoreUint64(r.cell(0), toBits(v))
+	for i := 1; i <= r.mask; i++ {
+		atomic.StoreUint64(r.cell(i), 0)
 	}
 }
+
+// fold sums values got from each cell. If check is set, it also counts spilled
+// additions and reports whether the sum exceeds range of T, see settle.
+func (r *randomCells[T]) fold(get func(*uint64) uint64, drain, check bool) (sum T, over int) {
+	var w wide
+	for i := 0; i <= r.mask; i++ {
+		v := fromBits[T](get(r.cell(i)))
+		if sum += v; check {
+			addWide(&w, v)
+		}
+	}
+
+	if check {
+		return settle[T](&r.overflow, w, drain)
+	}
+	return
+}
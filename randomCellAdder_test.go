@@ -2,6 +2,7 @@ package goadder
 
 import (
 	"testing"
+	"unsafe"
 )
 
 func TestRandomCellAdderNotRaceInc(t *testing.T) {
@@ -27,3 +28,40 @@ func TestRandomCellAdderNotRaceAdd(t *testing.T) {
 func TestRandomCellAdderRaceAdd(t *testing.T) {
 	testAdderRaceAdd(t, RandomCellAdderType)
 }
+
+func TestRandomCellAdderRaceDrain(t *testing.T) {
+	testAdderRaceDrain(t, RandomCellAdderType)
+}
+
+func TestNewRandomCellAdderWithSize(t *testing.T) {
+	for _, n := range []int{-4, 0, 3, 100} {
+		if _, err := NewRandomCellAdderWithSize(n); err != ErrCellSize {
+			t.Errorf("NewRandomCellAdderWithSize(%d) must fail", n)
+		}
+	}
+
+	for _, n := range []int{1, 2, 64, 1024} {
+		for _, padded := range []bool{false, true} {
+			var opts []Option
+			if padded {
+				opts = append(opts, WithPaddedCells())
+			}
+
+			adder, err := NewRandomCellAdderWithSize(n, opts...)
+			if err != nil {
+				t.Fatalf("NewRandomCellAdderWithSize(%d) must not fail", n)
+			}
+			if padded != (len(adder.padded) == n) || !padded != (len(adder.cells) == n) {
+				t.Errorf("RandomCellAdder of %d cells has wrong layout", n)
+			}
+			testOptionsRaceAdd(t, adder, 0)
+		}
+	}
+}
+
+func TestRandomCellAdderPaddedLayout(t *testing.T) {
+	adder := NewRandomCellAdder(WithPaddedCells())
+	if d := uintptr(unsafe.Pointer(&adder.padded[1].val)) - uintptr(unsafe.Pointer(&adder.padded[0].val)); d < 128 {
+		t.Errorf("Padded cells are only %d bytes apart", d)
+	}
+}
@@ -0,0 +1,149 @@
+package goadder
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrTypeConflict is returned when a name is already registered with another adder type.
+var ErrTypeConflict = errors.New("goadder: name registered with another adder type")
+
+// DefaultRegistry is the registry used by package-level GetOrCreateLongAdder, GetOrCreateFloat64Adder,
+// Unregister, Each and Snapshot.
+var DefaultRegistry = NewRegistry()
+
+// Entry is an adder registered by name. Exactly one of Long and Float is set.
+type Entry struct {
+	Name  string
+	Type  Type
+	Long  LongAdder
+	Float Float64Adder
+}
+
+// Registry holds adders by name, so that adders created across packages could be discovered
+// and exported altogether.
+//
+// Registry is safe for concurrent use. Looking up an existing name takes a read lock only, still
+// callers on hot paths should keep the returned adder rather than looking it up every update.
+type Registry struct {
+	lock    sync.RWMutex
+	entries map[string]Entry
+}
+
+// NewRegistry create new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]Entry)}
+}
+
+// GetOrCreateLongAdder returns long adder registered by name, creating it upon type with given options
+// if name is not registered yet. ErrTypeConflict is returned if name is registered with another type,
+// or with a float64 adder.
+func (r *Registry) GetOrCreateLongAdder(name string, t Type, opts ...Option) (LongAdder, error) {
+	e, err := r.getOrCreate(name, t, false, func() Entry {
+		return Entry{Long: NewLongAdder(t, opts...)}
+	})
+	return e.Long, err
+}
+
+// GetOrCreateFloat64Adder returns float64 adder registered by name, creating it upon type with given options
+// if name is not registered yet. ErrTypeConflict is returned if name is registered with another type,
+// or with a long adder.
+func (r *Registry) GetOrCreateFloat64Adder(name string, t Type, opts ...Option) (Float64Adder, error) {
+	e, err := r.getOrCreate(name, t, true, func() Entry {
+		return Entry{Float: NewFloat64Adder(t, opts...)}
+	})
+	return e.Float, err
+}
+
+func (r *Registry) getOrCreate(name string, t Type, float bool, create func() Entry) (Entry, error) {
+	r.lock.RLock()
+	e, ok := r.entries[name]
+	r.lock.RUnlock()
+
+	if !ok {
+		r.lock.Lock()
+		if e, ok = r.entries[name]; !ok {
+			e = create()
+			e.Name, e.Type = name, t
+			r.entries[name] = e
+		}
+		r.lock.Unlock()
+	}
+
+	if e.Type != t || (e.Float != nil) != float {
+		return Entry{}, ErrTypeConflict
+	}
+	return e, nil
+}
+
+// Unregister removes name, reporting whether it was registered. The adder itself is untouched.
+func (r *Registry) Unregister(name string) bool {
+	r.lock.Lock()
+	_, ok := r.entries[name]
+	delete(r.entries, name)
+	r.lock.Unlock()
+	return ok
+}
+
+// Each calls f for each registered adder, in order of name. If f returns false, Each stops.
+// f is called without holding the lock, so it may use the registry.
+func (r *Registry) Each(f func(e Entry) bool) {
+	for _, e := range r.sorted() {
+		if !f(e) {
+			return
+		}
+	}
+}
+
+func (r *Registry) sorted() []Entry {
+	r.lock.RLock()
+	entries := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Snapshot returns sum of each registered adder by name, long and float64 adders apart.
+// Like Sum of each adder, it is NOT an atomic snapshot.
+func (r *Registry) Snapshot() (longs map[string]int64, floats map[string]float64) {
+	longs, floats = make(map[string]int64), make(map[string]float64)
+	r.Each(func(e Entry) bool {
+		if e.Long != nil {
+			longs[e.Name] = e.Long.Sum()
+		} else {
+			floats[e.Name] = e.Float.Sum()
+		}
+		return true
+	})
+	return
+}
+
+// GetOrCreateLongAdder calls DefaultRegistry.GetOrCreateLongAdder.
+func GetOrCreateLongAdder(name string, t Type, opts ...Option) (LongAdder, error) {
+	return DefaultRegistry.GetOrCreateLongAdder(name, t, opts...)
+}
+
+// GetOrCreateFloat64Adder calls DefaultRegistry.GetOrCreateFloat64Adder.
+func GetOrCreateFloat64Adder(name string, t Type, opts ...Option) (Float64Adder, error) {
+	return DefaultRegistry.GetOrCreateFloat64Adder(name, t, opts...)
+}
+
+// Unregister calls DefaultRegistry.Unregister.
+func Unregister(name string) bool {
+	return DefaultRegistry.Unregister(name)
+}
+
+// Each calls DefaultRegistry.Each.
+func Each(f func(e Entry) bool) {
+	DefaultRegistry.Each(f)
+}
+
+// Snapshot calls DefaultRegistry.Snapshot.
+func Snapshot() (longs map[string]int64, floats map[string]float64) {
+	return DefaultRegistry.Snapshot()
+}
@@ -0,0 +1,89 @@
+package goadder
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			long, err := r.GetOrCreateLongAdder("requests", JDKAdderType)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			long.Inc()
+
+			float, err := r.GetOrCreateFloat64Adder("latency", KahanF64AdderType)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			float.Add(0.5)
+		}()
+	}
+	wg.Wait()
+
+	longs, floats := r.Snapshot()
+	if len(longs) != 1 || longs["requests"] != int64(numRoutine) {
+		t.Errorf("Registry logic is wrong: %v", longs)
+	}
+	if len(floats) != 1 || floats["latency"] != float64(numRoutine)/2 {
+		t.Errorf("Registry logic is wrong: %v", floats)
+	}
+
+	if _, err := r.GetOrCreateLongAdder("requests", AtomicAdderType); err != ErrTypeConflict {
+		t.Errorf("Registry must detect type conflict")
+	}
+	if _, err := r.GetOrCreateFloat64Adder("requests", JDKAdderType); err != ErrTypeConflict {
+		t.Errorf("Registry must detect type conflict")
+	}
+	if _, err := r.GetOrCreateLongAdder("latency", KahanF64AdderType); err != ErrTypeConflict {
+		t.Errorf("Registry must detect type conflict")
+	}
+
+	var names []string
+	r.Each(func(e Entry) bool {
+		names = append(names, e.Name)
+		return true
+	})
+	if len(names) != 2 || names[0] != "latency" || names[1] != "requests" {
+		t.Errorf("Each must walk adders in order of name: %v", names)
+	}
+
+	if !r.Unregister("requests") || r.Unregister("requests") {
+		t.Errorf("Unregister logic is wrong")
+	}
+	if long, err := r.GetOrCreateLongAdder("requests", AtomicAdderType); err != nil || long.Sum() != 0 {
+		t.Errorf("Unregistered name must be reusable")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	long, err := GetOrCreateLongAdder("test.default.requests", PerPAdderType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("test.default.requests")
+	long.Add(3)
+
+	if longs, _ := Snapshot(); longs["test.default.requests"] != 3 {
+		t.Errorf("Default registry logic is wrong")
+	}
+
+	var found bool
+	Each(func(e Entry) bool {
+		found = found || e.Long == long
+		return true
+	})
+	if !found {
+		t.Errorf("Default registry logic is wrong")
+	}
+}
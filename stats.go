@@ -0,0 +1,101 @@
+package goadder
+
+import (
+	"sync/atomic"
+)
+
+// WithContentionStats enables contention counters reported by Stats. Counters are only
+// updated on contended paths, but they are shared by all routines updating the adder.
+func WithContentionStats() Option {
+	return func(c *config) {
+		c.contentionStats = true
+	}
+}
+
+// ContentionStats holds contention counters of a striped adder.
+type ContentionStats struct {
+	// BaseCASFailures is number of failed CAS on base.
+	BaseCASFailures uint64
+	// CellCASFailures is number of failed CAS on cells.
+	CellCASFailures uint64
+	// Rehashes is number of times a routine probe was rehashed to another cell after a collision.
+	Rehashes uint64
+	// Growths is number of times the cell table was doubled.
+	Growths uint64
+}
+
+func (c *ContentionStats) baseCASFailed() {
+	if c != nil {
+		atomic.AddUint64(&c.BaseCASFailures, 1)
+	}
+}
+
+func (c *ContentionStats) cellCASFailed() {
+	if c != nil {
+		atomic.AddUint64(&c.CellCASFailures, 1)
+	}
+}
+
+func (c *ContentionStats) rehashed() {
+	if c != nil {
+		atomic.AddUint64(&c.Rehashes, 1)
+	}
+}
+
+func (c *ContentionStats) grew() {
+	if c != nil {
+		atomic.AddUint64(&c.Growths, 1)
+	}
+}
+
+func (c *ContentionStats) snapshot() (s ContentionStats) {
+	if c != nil {
+		s.BaseCASFailures = atomic.LoadUint64(&c.BaseCASFailures)
+		s.CellCASFailures = atomic.LoadUint64(&c.CellCASFailures)
+		s.Rehashes = atomic.LoadUint64(&c.Rehashes)
+		s.Growths = atomic.LoadUint64(&c.Growths)
+	}
+	return
+}
+
+// TableStats describes cell table of a Striped. The values are NOT an
+// atomic snapshot because of concurrent update.
+type TableStats[T Number] struct {
+	// TableLen is length of cell table, zero if table is not yet initialized.
+	TableLen int
+	// TableCap is capacity of cell table.
+	TableCap int
+	// PopulatedCells is number of created cells in table.
+	PopulatedCells int
+	// Base is value of base field.
+	Base T
+	// ContentionStats are only maintained if adder is created with WithContentionStats.
+	ContentionStats
+}
+
+// StripedStats describes cell table of a Striped64.
+type StripedStats = TableStats[int64]
+
+// StripedF64Stats describes cell table of a StripedF64.
+type StripedF64Stats = TableStats[float64]
+
+// Stats returns current cell table and contention statistics.
+func (s *Striped[T]) Stats() (st TableStats[T]) {
+	st.Base = s.base.load()
+	st.TableLen, st.TableCap, st.PopulatedCells = tableStats(s.cells.Load())
+	st.ContentionStats = s.contention.snapshot()
+	return
+}
+
+func tableStats(_as interface{}) (length, capacity, populated int) {
+	if _as != nil {
+		as := _as.(cells)
+		length, capacity = len(as), cap(as)
+		for i := range as {
+			if as[i].Load() != nil {
+				populated++
+			}
+		}
+	}
+	return
+}
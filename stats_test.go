@@ -0,0 +1,136 @@
+package goadder
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestJDKAdderStats(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithContentionStats(), WithInitialCells(4), WithMaxCells(16))
+
+	adder.Add(5)
+	if st := adder.Stats(); st.Base != 5 || st.TableLen != 0 || st.PopulatedCells != 0 || st.ContentionStats != (ContentionStats{}) {
+		t.Errorf("Stats of uncontended adder is wrong: %+v", st)
+	}
+
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+	if st := adder.Stats(); st.TableLen != 4 || st.TableCap != 8 || st.PopulatedCells != 1 {
+		t.Errorf("Stats of initialized table is wrong: %+v", st)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRoutine; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < delta/10; j++ {
+				adder.Inc()
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	st := adder.Stats()
+	if st.TableLen > 16 || st.PopulatedCells > st.TableLen || st.TableLen > st.TableCap {
+		t.Errorf("Stats is wrong: %+v", st)
+	}
+	if st.Growths > 0 && st.TableLen == 4 {
+		t.Errorf("Growths is wrong: %+v", st)
+	}
+	if adder.Sum() != int64(delta/10*numRoutine+6) {
+		t.Errorf("Adder logic is wrong")
+	}
+}
+
+func TestJDKAdderStatsContended(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithContentionStats(), WithMaxCells(64))
+
+	// a racing routine updates base and every cell between load and CAS of each update,
+	// so that contention does not depend on the number of CPUs
+	races := 0
+	race := BinaryFunc[int64](func(left, right int64) int64 {
+		if races > 0 {
+			races--
+			adder.base.cas(adder.base.load(), adder.base.load()+1)
+			if _as := adder.cells.Load(); _as != nil {
+				as := _as.(cells)
+				for i := range as {
+					if a := as[i].Load(); a != nil {
+						c := a.(*cell[int64])
+						c.cas(c.load(), c.load()+1)
+					}
+				}
+			}
+		}
+		return left + right
+	})
+
+	// base is the fallback while the table is busy
+	atomic.StoreInt32(&adder.cellsBusy, 1)
+	races = 2
+	adder.accumulate(getRandomInt(), 1, race, false, true)
+	atomic.StoreInt32(&adder.cellsBusy, 0)
+
+	for races = 256; races > 0; {
+		adder.accumulate(getRandomInt(), 1, race, false, true)
+	}
+
+	st := adder.Stats()
+	if st.BaseCASFailures == 0 || st.CellCASFailures == 0 || st.Rehashes == 0 || st.Growths == 0 {
+		t.Errorf("Contention must be counted: %+v", st)
+	}
+	if st.Rehashes+st.Growths != st.CellCASFailures {
+		t.Errorf("Rehashes must only follow collisions: %+v", st)
+	}
+}
+
+func TestJDKAdderStatsEmptyCell(t *testing.T) {
+	adder := NewJDKAdderWithOptions(WithContentionStats(), WithInitialCells(4))
+	adder.accumulate(1, 1, nil, false, true)
+	adder.accumulate(2, 1, nil, false, true)
+	if st := adder.Stats(); st.PopulatedCells != 2 || st.ContentionStats != (ContentionStats{}) {
+		t.Errorf("Attaching a cell is not a rehash: %+v", st)
+	}
+}
+
+func TestJDKAdderStatsDisabled(t *testing.T) {
+	adder := NewJDKAdder()
+	adder.accumulate(getRandomInt(), 1, nil, false, false)
+	adder.accumulate(getRandomInt(), 1, nil, false, false)
+	if st := adder.Stats(); st.ContentionStats != (ContentionStats{}) || st.TableLen != 2 {
+		t.Errorf("Stats is wrong: %+v", st)
+	}
+}
+
+func TestJDKF64AdderStats(t *testing.T) {
+	adder := NewJDKF64AdderWithOptions(WithContentionStats())
+
+	adder.Add(1.5)
+	if st := adder.Stats(); st.Base != 1.5 || st.TableLen != 0 {
+		t.Errorf("Stats of uncontended adder is wrong: %+v", st)
+	}
+
+	adder.accumulate(getRandomInt(), 1, nil, false, true)
+	if st := adder.Stats(); st.TableLen != 2 || st.TableCap != 4 || st.PopulatedCells != 1 {
+		t.Errorf("Stats of initialized table is wrong: %+v", st)
+	}
+}
+
+func TestContentionStatsCounting(t *testing.T) {
+	var c ContentionStats
+	c.baseCASFailed()
+	c.cellCASFailed()
+	c.cellCASFailed()
+	c.rehashed()
+	c.grew()
+	if s := c.snapshot(); s != (ContentionStats{BaseCASFailures: 1, CellCASFailures: 2, Rehashes: 1, Growths: 1}) {
+		t.Errorf("Counting is wrong: %+v", s)
+	}
+
+	var nilStats *ContentionStats
+	nilStats.baseCASFailed()
+	if nilStats.snapshot() != (ContentionStats{}) {
+		t.Errorf("Nil stats must be zero")
+	}
+}
@@ -0,0 +1,266 @@
+// Package statsd periodically pushes adders as counters to a StatsD or DogStatsD agent over UDP.
+//
+// Every interval, the exporter drains each adder by SumAndReset and sends the delta as a
+// `name:value|c|#tags` line. Lines are batched into packets up to the MTU.
+//
+// Delta semantics rely on SumAndReset not losing updates made concurrently with it. All adders of
+// goadder are safe in that respect: JDKAdder, AdaptiveAdder, RandomCellAdder, PerPAdder, AtomicAdder,
+// MutexAdder, their uint64 variants, JDKF64Adder, KahanF64Adder, AtomicF64Adder and ExemplarAdder backed
+// by any of them. Other implementations of goadder.Adder must provide the same guarantee. Since adders
+// are drained, they must not be exported elsewhere as cumulative counters, e.g. by package exposition.
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+const (
+	// DefaultInterval is the default interval between pushes.
+	DefaultInterval = 10 * time.Second
+	// DefaultMTU is the default maximum size of a packet, fitting an Ethernet frame with IP and UDP headers.
+	DefaultMTU = 1432
+)
+
+// Option configures Exporter.
+type Option func(*Exporter)
+
+// WithInterval sets interval between pushes. Non-positive value keeps DefaultInterval.
+func WithInterval(d time.Duration) Option {
+	return func(e *Exporter) {
+		if d > 0 {
+			e.interval = d
+		}
+	}
+}
+
+// WithPrefix prepends prefix and a dot to every metric name.
+func WithPrefix(prefix string) Option {
+	return func(e *Exporter) {
+		if prefix != "" {
+			e.prefix = sanitize(strings.TrimSuffix(prefix, ".")) + "."
+		}
+	}
+}
+
+// WithMTU sets maximum size of a packet. Non-positive value keeps DefaultMTU.
+func WithMTU(n int) Option {
+	return func(e *Exporter) {
+		if n > 0 {
+			e.mtu = n
+		}
+	}
+}
+
+// WithTags adds DogStatsD tags, such as "env:prod", to every metric.
+func WithTags(tags ...string) Option {
+	return func(e *Exporter) {
+		e.tags = append(e.tags, tags...)
+	}
+}
+
+// Exporter periodically drains adders and pushes their deltas to a StatsD agent.
+//
+// Exporter is safe for concurrent use.
+type Exporter struct {
+	conn     net.Conn
+	prefix   string
+	interval time.Duration
+	mtu      int
+	tags     []string
+
+	lock     sync.Mutex
+	sources  []source
+	flush    sync.Mutex
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// source drains adders, emitting a line per non-zero delta.
+type source func(emit func(name, tags, value string))
+
+// NewExporter create new Exporter pushing to the agent at addr, e.g. "127.0.0.1:8125".
+// Stop must be called to push the last deltas and release the background routine.
+func NewExporter(addr string, opts ...Option) (*Exporter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Exporter{
+		conn:     conn,
+		interval: DefaultInterval,
+		mtu:      DefaultMTU,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	e.wg.Add(1)
+	go e.run()
+
+	return e, nil
+}
+
+// AddLong pushes deltas of the given long adder as counter name, with given tags.
+func (e *Exporter) AddLong(name string, adder goadder.LongAdder, tags ...string) {
+	name, rendered := e.prefix+sanitize(name), e.renderTags(tags)
+	e.add(func(emit func(name, tags, value string)) {
+		if v := adder.SumAndReset(); v != 0 {
+			emit(name, rendered, strconv.FormatInt(v, 10))
+		}
+	})
+}
+
+// AddFloat pushes deltas of the given float64 adder as counter name, with given tags.
+// NaN and infinite deltas are dropped.
+func (e *Exporter) AddFloat(name string, adder goadder.Float64Adder, tags ...string) {
+	name, rendered := e.prefix+sanitize(name), e.renderTags(tags)
+	e.add(func(emit func(name, tags, value string)) {
+		if v := adder.SumAndReset(); v != 0 && !math.IsNaN(v) && !math.IsInf(v, 0) {
+			emit(name, rendered, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	})
+}
+
+// AddMap pushes deltas of all adders of an AdderMap as counter name, see AdderMap.DrainAll. Each key is
+// formatted by fmt.Sprint as value of tag key, next to the given tags. Keys created later are pushed as well.
+func AddMap[K comparable](e *Exporter, name, key string, m *goadder.AdderMap[K], tags ...string) {
+	name = e.prefix + sanitize(name)
+	e.add(func(emit func(name, tags, value string)) {
+		for k, v := range m.DrainAll() {
+			if v != 0 {
+				emit(name, e.renderTags(append(tags[:len(tags):len(tags)], key+":"+fmt.Sprint(k))), strconv.FormatInt(v, 10))
+			}
+		}
+	})
+}
+
+// AddRegistry pushes deltas of all adders of a registry, named after their registry names, with given tags.
+// Adders registered later are pushed as well.
+func (e *Exporter) AddRegistry(r *goadder.Registry, tags ...string) {
+	rendered := e.renderTags(tags)
+	e.add(func(emit func(name, tags, value string)) {
+		r.Each(func(entry goadder.Entry) bool {
+			name := e.prefix + sanitize(entry.Name)
+			if entry.Long != nil {
+				if v := entry.Long.SumAndReset(); v != 0 {
+					emit(name, rendered, strconv.FormatInt(v, 10))
+				}
+			} else if v := entry.Float.SumAndReset(); v != 0 && !math.IsNaN(v) && !math.IsInf(v, 0) {
+				emit(name, rendered, strconv.FormatFloat(v, 'f', -1, 64))
+			}
+			return true
+		})
+	})
+}
+
+func (e *Exporter) add(s source) {
+	e.lock.Lock()
+	e.sources = append(e.sources, s)
+	e.lock.Unlock()
+}
+
+// Flush drains adders and pushes their deltas now. Deltas of a packet failed to be sent are lost,
+// the first error is returned.
+func (e *Exporter) Flush() (err error) {
+	e.flush.Lock()
+	defer e.flush.Unlock()
+
+	e.lock.Lock()
+	sources := e.sources
+	e.lock.Unlock()
+
+	var packet bytes.Buffer
+	send := func() {
+		if packet.Len() > 0 {
+			if _, werr := e.conn.Write(packet.Bytes()); werr != nil && err == nil {
+				err = werr
+			}
+			packet.Reset()
+		}
+	}
+
+	var line []byte
+	for _, s := range sources {
+		s(func(name, tags, value string) {
+			line = append(line[:0], name...)
+			line = append(line, ':')
+			line = append(line, value...)
+			line = append(line, "|c"...)
+			if tags != "" {
+				line = append(line, "|#"...)
+				line = append(line, tags...)
+			}
+
+			if packet.Len() > 0 && packet.Len()+1+len(line) > e.mtu {
+				send()
+			}
+			if packet.Len() > 0 {
+				packet.WriteByte('\n')
+			}
+			packet.Write(line)
+		})
+	}
+	send()
+
+	return
+}
+
+// Stop background routine, pushes the last deltas and closes the connection.
+func (e *Exporter) Stop() (err error) {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+		e.wg.Wait()
+
+		err = e.Flush()
+		if cerr := e.conn.Close(); err == nil {
+			err = cerr
+		}
+	})
+	return
+}
+
+func (e *Exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			_ = e.Flush()
+		}
+	}
+}
+
+// renderTags joins constant tags of exporter and the given ones by comma.
+func (e *Exporter) renderTags(tags []string) string {
+	all := make([]string, 0, len(e.tags)+len(tags))
+	for _, tag := range e.tags {
+		all = append(all, sanitizeTag(tag))
+	}
+	for _, tag := range tags {
+		all = append(all, sanitizeTag(tag))
+	}
+	return strings.Join(all, ",")
+}
+
+// sanitize replaces characters delimiting the StatsD line format in a metric name by underscore.
+var sanitize = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ",", "_", "\n", "_").Replace
+
+// sanitizeTag replaces characters delimiting DogStatsD tags by underscore, colon separates name and value.
+var sanitizeTag = strings.NewReplacer("|", "_", "@", "_", "#", "_", ",", "_", "\n", "_").Replace
@@ -0,0 +1,176 @@
+package statsd
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goadder "github.com/linxGnu/go-adder"
+)
+
+// listen returns a local UDP listener and a function reading its packets until timeout.
+func listen(t *testing.T) (net.PacketConn, func(timeout time.Duration) []string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, func(timeout time.Duration) (packets []string) {
+		buf := make([]byte, 65536)
+		for {
+			conn.SetReadDeadline(time.Now().Add(timeout))
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			packets = append(packets, string(buf[:n]))
+		}
+	}
+}
+
+func lines(packets []string) (l []string) {
+	for _, p := range packets {
+		l = append(l, strings.Split(p, "\n")...)
+	}
+	sort.Strings(l)
+	return
+}
+
+func TestExporter(t *testing.T) {
+	conn, read := listen(t)
+	defer conn.Close()
+
+	e, err := NewExporter(conn.LocalAddr().String(), WithPrefix("app."), WithTags("env:test"), WithInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := goadder.NewLongAdder(goadder.JDKAdderType)
+	e.AddLong("http.requests", requests, "method:get")
+	latency := goadder.NewFloat64Adder(goadder.KahanF64AdderType)
+	e.AddFloat("latency|ms", latency)
+	m := goadder.NewAdderMap[int](goadder.PerPAdderType, 0, 0)
+	AddMap(e, "responses", "code", m)
+	r := goadder.NewRegistry()
+	jobs, _ := r.GetOrCreateLongAdder("jobs", goadder.AtomicAdderType)
+	e.AddRegistry(r, "queue:mail")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 1000; j++ {
+				requests.Inc()
+				m.Inc(200 + j%2*300)
+			}
+			wg.Done()
+		}()
+	}
+	latency.Add(1.25)
+	jobs.Add(-3)
+
+	// push concurrently with updates, deltas must add up
+	var flushed []string
+	for i := 0; i < 3; i++ {
+		if err := e.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		flushed = append(flushed, read(50*time.Millisecond)...)
+	}
+	wg.Wait()
+	if err := e.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	flushed = append(flushed, read(50*time.Millisecond)...)
+
+	sums := make(map[string]float64)
+	for _, line := range lines(flushed) {
+		i, j := strings.IndexByte(line, ':'), strings.IndexByte(line, '|')
+		v, err := strconv.ParseFloat(line[i+1:j], 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sums[line[:i]+line[j:]] += v
+	}
+
+	expected := map[string]float64{
+		"app.http.requests|c|#env:test,method:get": 4000,
+		"app.latency_ms|c|#env:test":               1.25,
+		"app.responses|c|#env:test,code:200":       2000,
+		"app.responses|c|#env:test,code:500":       2000,
+		"app.jobs|c|#env:test,queue:mail":          -3,
+	}
+	if len(sums) != len(expected) {
+		t.Errorf("Exporter logic is wrong: %v", sums)
+	}
+	for k, v := range expected {
+		if sums[k] != v {
+			t.Errorf("Exporter logic is wrong: %s = %v, expected %v", k, sums[k], v)
+		}
+	}
+}
+
+func TestExporterMTU(t *testing.T) {
+	conn, read := listen(t)
+	defer conn.Close()
+
+	e, err := NewExporter(conn.LocalAddr().String(), WithMTU(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	var expected []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "a_very_long_name_exceeding_mtu_is_sent_alone_in_its_own_packet"} {
+		adder := goadder.NewLongAdder(goadder.AtomicAdderType)
+		adder.Add(12345)
+		e.AddLong(name, adder)
+		expected = append(expected, name+":12345|c")
+	}
+	e.AddLong("zero", goadder.NewLongAdder(goadder.AtomicAdderType)) // zero deltas are not sent
+
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	packets := read(50 * time.Millisecond)
+	if len(packets) != 3 {
+		t.Errorf("Lines must be batched up to MTU: %q", packets)
+	}
+	for _, p := range packets[:len(packets)-1] {
+		if len(p) > 64 {
+			t.Errorf("Packet exceeds MTU: %q", p)
+		}
+	}
+	sort.Strings(expected)
+	if actual := lines(packets); strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Exporter logic is wrong: %q", actual)
+	}
+
+	// nothing to push
+	if err := e.Flush(); err != nil || len(read(50*time.Millisecond)) != 0 {
+		t.Errorf("Drained adders must not be pushed again")
+	}
+}
+
+func TestExporterInterval(t *testing.T) {
+	conn, read := listen(t)
+	defer conn.Close()
+
+	e, err := NewExporter(conn.LocalAddr().String(), WithInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	adder := goadder.NewLongAdder(goadder.JDKAdderType)
+	adder.Add(7)
+	e.AddLong("ticks", adder)
+
+	if packets := read(200 * time.Millisecond); len(packets) != 1 || packets[0] != "ticks:7|c" {
+		t.Errorf("Exporter must push every interval: %q", packets)
+	}
+}
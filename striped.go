@@ -0,0 +1,276 @@
+package goadder
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+)
+
+var maxCells = runtime.NumCPU() << 2
+
+func init() {
+	if maxCells > (1 << 11) {
+		maxCells = (1 << 11)
+	}
+
+	if maxCells < 64 {
+		maxCells = 64
+	}
+}
+
+type cells []atomic.Value
+
+// cell holds value of type T encoded as uint64 bits, see toBits.
+type cell[T Number] struct {
+	_       [7]uint64
+	val     uint64
+	retired int32
+	_       int32 // keep size a multiple of 8, so that val stays 64-bit aligned in slices of cells on 32-bit platforms
+	_       [7]uint64
+}
+
+func (c *cell[T]) load() T {
+	return fromBits[T](atomic.LoadUint64(&c.val))
+}
+
+func (c *cell[T]) store(v T) {
+	atomic.StoreUint64(&c.val, toBits(v))
+}
+
+func (c *cell[T]) swap(v T) T {
+	return fromBits[T](atomic.SwapUint64(&c.val, toBits(v)))
+}
+
+func (c *cell[T]) cas(old, new T) bool {
+	return atomic.CompareAndSwapUint64(&c.val, toBits(old), toBits(new))
+}
+
+func (c *cell[T]) isRetired() bool {
+	return atomic.LoadInt32(&c.retired) != 0
+}
+
+// isFloat reports whether T is a floating-point type.
+func isFloat[T Number]() bool {
+	var half T = 1
+	half /= 2
+	return half != 0
+}
+
+// toBits encodes v into uint64 bits. Integers are sign-extended, so that every value has an unique
+// encoding and CAS on encoded bits is equivalent to CAS on integer values.
+func toBits[T Number](v T) uint64 {
+	if isFloat[T]() {
+		return math.Float64bits(float64(v))
+	}
+	return uint64(v)
+}
+
+// fromBits decodes uint64 bits encoded by toBits.
+func fromBits[T Number](b uint64) T {
+	if isFloat[T]() {
+		return T(math.Float64frombits(b))
+	}
+	return T(b)
+}
+
+// Striped is ported version of OpenJDK9 Striped64, generic over the numeric type of cells.
+// It maintains a lazily-initialized table of atomically
+// updated variables, plus an extra "base" field. The table size
+// is a power of two. Indexing uses masked per-routine hash codes.
+// Nearly all declarations in this class are package-private,
+// accessed directly by subclasses.
+//
+// In part because Cells are relatively large, we avoid creating
+// them until they are needed. When there is no contention, all
+// updates are made to the base field. Upon first contention (a
+// failed CAS on base update), the table is initialized to size 2 and cap 4.
+// The table size is doubled upon further contention until
+// reaching the nearest power of two greater than or equal to the
+// number of CPUS. Both initial size and maximum size could be
+// tuned per instance, see WithInitialCells and WithMaxCells.
+// Table slots remain empty (null) until they are needed.
+//
+// A single spinlock ("cellsBusy") is used for initializing and
+// resizing the table, as well as populating slots with new Cells.
+// There is no need for a blocking lock; when the lock is not
+// available, routines try other slots (or the base). During these
+// retries, there is increased contention and reduced locality,
+// which is still better than alternatives.
+//
+// The routine probe maintain by SystemTime nanoseconds instead of OpenJDK ThreadLocalRandom.
+// Contention and/or table collisions are indicated by failed CASes when performing an update
+// operation. Upon a collision, if the table size is less than
+// the capacity, it is doubled in size unless some other routine
+// holds the lock. If a hashed slot is empty, and lock is
+// available, a new Cell is created. Otherwise, if the slot
+// exists, a CAS is tried. Retries proceed with reproducing probe.
+//
+// The table size is capped because, when there are more routines
+// than CPUs, supposing that each routine were bound to a CPU,
+// there would exist a perfect hash function mapping routines to
+// slots that eliminates collisions. When we reach capacity, we
+// search for this mapping by randomly varying the hash codes of
+// colliding routines. Because search is random, and collisions
+// only become known via CAS failures, convergence can be slow,
+// and because routines are typically not bound to CPUS forever,
+// may not occur at all. However, despite these limitations,
+// observed contention rates are typically low in these cases.
+//
+// It is possible for a Cell to become unused when routines that
+// once hashed to it terminate, as well as in the case where
+// doubling the table causes no routine to hash to it under
+// expanded mask. We do not try to detect or remove such cells
+// automatically, under the assumption that for long-running instances,
+// observed contention levels will recur, so the cells will eventually be
+// needed again; and for short-lived ones, it does not matter.
+// For instances whose contention does not recur, cells could be folded back
+// into base by compaction on demand, see Compactor.
+//
+// Compaction publishes a fresh table first, then retires each old Cell
+// and moves its value into base. A routine which successfully updated a
+// Cell checks the retired flag afterward and, if set, moves the Cell value
+// into base itself. Since either the compaction sweep or the routine sweep
+// happens after every update, no update is lost in a retired Cell.
+type Striped[T Number] struct {
+	table
+	base     cell[T]
+	identity T
+
+	// onWrap, if not nil, takes over additions which would wrap base or a cell around. neg tells whether x is subtracted.
+	onWrap func(x T, neg bool)
+}
+
+// Striped64 is Striped of int64 cells.
+type Striped64 = Striped[int64]
+
+// StripedF64 is Striped of float64 cells.
+type StripedF64 = Striped[float64]
+
+func (s *Striped[T]) init(opts []Option) {
+	s.conf = newConfig(opts)
+	if s.conf.contentionStats {
+		s.contention = &ContentionStats{}
+	}
+}
+
+// sweep moves value of a retired cell into base.
+func (s *Striped[T]) sweep(a *cell[T]) {
+	v := a.swap(0)
+	if v == 0 {
+		return
+	}
+	for {
+		b := s.base.load()
+		if s.wraps(b, b+v, nil, v < 0) {
+			s.onWrap(v, v < 0)
+			return
+		}
+		if s.base.cas(b, b+v) {
+			return
+		}
+	}
+}
+
+// compact folds all cells into base and replaces cell table with a fresh one of initial size.
+func (s *Striped[T]) compact() {
+	for !s.casCellsBusy() {
+		runtime.Gosched()
+	}
+
+	if _as := s.cells.Load(); _as != nil {
+		as := _as.(cells)
+		s.cells.Store(s.conf.newTable())
+
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				atomic.StoreInt32(&a.(*cell[T]).retired, 1)
+				s.sweep(a.(*cell[T]))
+			}
+		}
+	}
+
+	atomic.StoreInt32(&s.cellsBusy, 0)
+}
+
+// fingerprint digests values of cells. The second value is false if there is no populated cell.
+func (s *Striped[T]) fingerprint() (h uint64, populated bool) {
+	if _as := s.cells.Load(); _as != nil {
+		as := _as.(cells)
+		var a interface{}
+		for i := range as {
+			if a = as[i].Load(); a != nil {
+				h = h*31 + atomic.LoadUint64(&a.(*cell[T]).val)
+				populated = true
+			}
+		}
+	}
+	return
+}
+
+// wraps reports whether adding x by updating old to new wraps around and should be taken over by onWrap.
+func (s *Striped[T]) wraps(old, new T, fn BinaryOperator[T], neg bool) bool {
+	return fn == nil && s.onWrap != nil && wrapped(old, new, neg)
+}
+
+// accumulate applies x to base or a cell upon contention. If fn is nil, x is added and neg tells
+// whether x is negative or subtracting.
+func (s *Striped[T]) accumulate(probe int, x T, fn BinaryOperator[T], neg, wasUncontended bool) {
+	if probe == 0 {
+		probe = getRandomInt()
+		wasUncontended = true
+	}
+	accumulate[cell[T]](&s.table, probe, stripedUpdate[T]{s: s, x: x, fn: fn, neg: neg}, wasUncontended)
+}
+
+// stripedUpdate applies x to base or a cell of Striped, see accumulate.
+type stripedUpdate[T Number] struct {
+	s   *Striped[T]
+	x   T
+	fn  BinaryOperator[T]
+	neg bool
+}
+
+func (u stripedUpdate[T]) apply(v T) T {
+	if u.fn == nil {
+		return v + u.x
+	}
+	return u.fn.Apply(v, u.x)
+}
+
+func (u stripedUpdate[T]) tryBase() bool {
+	v := u.s.base.load()
+	newV := u.apply(v)
+	if u.s.wraps(v, newV, u.fn, u.neg) {
+		u.s.onWrap(u.x, u.neg)
+		return true
+	}
+	return u.s.base.cas(v, newV)
+}
+
+func (u stripedUpdate[T]) tryCell(a *cell[T]) bool {
+	v := a.load()
+	newV := u.apply(v)
+	if u.s.wraps(v, newV, u.fn, u.neg) {
+		u.s.onWrap(u.x, u.neg)
+		return true
+	}
+	if !a.cas(v, newV) {
+		return false
+	}
+	if a.isRetired() {
+		u.s.sweep(a)
+	}
+	return true
+}
+
+// newCell creates a cell holding the result of applying x upon identity.
+func (u stripedUpdate[T]) newCell() *cell[T] {
+	if u.s.wraps(u.s.identity, u.apply(u.s.identity), u.fn, u.neg) {
+		u.s.onWrap(u.x, u.neg)
+		return nil
+	}
+	c := &cell[T]{}
+	c.store(u.apply(u.s.identity))
+	return c
+}
@@ -0,0 +1,112 @@
+package goadder
+
+import (
+	"sync/atomic"
+)
+
+// table is a table of cells, created upon first contention and doubled upon contention
+// on its cells, like that of Striped64. It is shared by Striped, KahanF64Adder and exemplars, which
+// differ by their cells and how an update is applied to them, see tableUpdate.
+type table struct {
+	cells      atomic.Value
+	cellsBusy  int32
+	conf       config
+	contention *ContentionStats
+}
+
+// tableUpdate is applied by accumulate to base or a cell of type C.
+type tableUpdate[C any] interface {
+	// tryBase applies the update to base, false if it failed because of contention.
+	tryBase() bool
+	// tryCell applies the update to c, false if it failed because of contention.
+	tryCell(c *C) bool
+	// newCell creates a cell holding the update, or returns nil if the update was taken over otherwise.
+	newCell() *C
+}
+
+func (t *table) casCellsBusy() bool {
+	return atomic.CompareAndSwapInt32(&t.cellsBusy, 0, 1)
+}
+
+// accumulate applies u to base or a cell upon contention. It handles initialization, attaching new
+// Cells, growing the table and rehashing probe upon collision. wasUncontended is false if the caller
+// already failed to update the cell of probe.
+func accumulate[C any, U tableUpdate[C]](t *table, probe int, u U, wasUncontended bool) {
+	collide := false
+	for {
+		_as := t.cells.Load()
+		if _as == nil {
+			if atomic.LoadInt32(&t.cellsBusy) == 0 && t.cells.Load() == nil && t.casCellsBusy() {
+				if t.cells.Load() == nil { // Initialize table
+					rs := t.conf.newTable()
+					if r := u.newCell(); r != nil {
+						rs[probe&(len(rs)-1)].Store(r)
+					}
+					t.cells.Store(rs)
+					atomic.StoreInt32(&t.cellsBusy, 0)
+					return
+				}
+				atomic.StoreInt32(&t.cellsBusy, 0)
+			} else if u.tryBase() { // Fall back on using base
+				return
+			} else {
+				t.contention.baseCASFailed()
+			}
+			continue
+		}
+
+		as := _as.(cells)
+		if _a := as[probe&(len(as)-1)].Load(); _a == nil {
+			if atomic.LoadInt32(&t.cellsBusy) == 0 { // Try to attach new Cell
+				r := u.newCell() // Optimistically create
+				if r == nil {
+					return
+				}
+				if atomic.LoadInt32(&t.cellsBusy) == 0 && t.casCellsBusy() {
+					rs := t.cells.Load().(cells)
+					if j := probe & (len(rs) - 1); rs[j].Load() == nil { // Recheck under lock
+						rs[j].Store(r)
+						atomic.StoreInt32(&t.cellsBusy, 0)
+						return
+					}
+					atomic.StoreInt32(&t.cellsBusy, 0)
+					continue
+				}
+			}
+			collide = false
+		} else if !wasUncontended { // CAS already known to fail
+			wasUncontended = true // Continue after rehash
+			t.contention.rehashed()
+		} else if u.tryCell(_a.(*C)) {
+			return
+		} else {
+			t.contention.cellCASFailed()
+			if len(as) >= t.conf.limit() || &as[0] != &t.cells.Load().(cells)[0] { // At max size or stale
+				collide = false
+			} else if !collide {
+				collide = true
+			} else if atomic.LoadInt32(&t.cellsBusy) == 0 && t.casCellsBusy() {
+				if rs := t.cells.Load().(cells); &as[0] == &rs[0] { // double size of cells
+					if n := cap(as); len(as) < n {
+						t.cells.Store(rs[:n])
+					} else {
+						// slice is full, n == len(as) then we just x4 size for buffering
+						// Note: this trick is different from jdk source code
+						rs = make(cells, n<<1, t.conf.capacity(n<<2))
+						copy(rs, as)
+						t.cells.Store(rs)
+					}
+					t.contention.grew()
+				}
+				atomic.StoreInt32(&t.cellsBusy, 0)
+				collide = false
+				continue
+			}
+			t.contention.rehashed()
+		}
+
+		probe ^= probe << 13 // xorshift
+		probe ^= probe >> 17
+		probe ^= probe << 5
+	}
+}
@@ -0,0 +1,49 @@
+package goadder
+
+// Uint64Adder interface
+type Uint64Adder = Adder[uint64]
+
+// JDKUint64Adder is JDK-based striped adder of uint64, see StripedAdder.
+type JDKUint64Adder = StripedAdder[uint64]
+
+// RandomCellUint64Adder is RandomCellAdder of uint64.
+type RandomCellUint64Adder struct {
+	randomCells[uint64]
+}
+
+// AtomicUint64Adder is AtomicAdder of uint64.
+type AtomicUint64Adder struct {
+	atomicValue[uint64]
+}
+
+// NewUint64Adder create new uint64 adder upon type. Like Prometheus counters, uint64 adders wrap around
+// silently by default. Use WithOverflowCheck to detect sums exceeding math.MaxUint64 (or Dec below zero).
+func NewUint64Adder(t Type, opts ...Option) Uint64Adder {
+	switch t {
+	case AtomicAdderType:
+		return NewAtomicUint64Adder(opts...)
+	case RandomCellAdderType:
+		return NewRandomCellUint64Adder(opts...)
+	default:
+		return NewJDKUint64Adder(opts...)
+	}
+}
+
+// NewJDKUint64Adder create new JDKUint64Adder
+func NewJDKUint64Adder(opts ...Option) *JDKUint64Adder {
+	return NewStripedAdder[uint64](opts...)
+}
+
+// NewRandomCellUint64Adder create new RandomCellUint64Adder
+func NewRandomCellUint64Adder(opts ...Option) *RandomCellUint64Adder {
+	r := &RandomCellUint64Adder{}
+	r.init(randomCellSize, opts)
+	return r
+}
+
+// NewAtomicUint64Adder create new AtomicUint64Adder
+func NewAtomicUint64Adder(opts ...Option) *AtomicUint64Adder {
+	a := &AtomicUint64Adder{}
+	a.init(opts)
+	return a
+}
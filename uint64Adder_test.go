@@ -0,0 +1,183 @@
+package goadder
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var uint64AdderTypes = []Type{JDKAdderType, RandomCellAdderType, AtomicAdderType}
+
+func TestUint64AdderRace(t *testing.T) {
+	for _, ty := range uint64AdderTypes {
+		adder := NewUint64Adder(ty)
+
+		var wg sync.WaitGroup
+		for i := 0; i < numRoutine; i++ {
+			wg.Add(1)
+			go func() {
+				for j := 0; j < delta/10; j++ {
+					adder.Add(3)
+					adder.Dec()
+				}
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+
+		tmp := uint64(delta/10) * 2 * uint64(numRoutine)
+		if adder.Sum() != tmp || adder.SumAndReset() != tmp || adder.Sum() != 0 {
+			t.Errorf("Uint64Adder(%d) logic is wrong", ty)
+		}
+
+		if adder.Store(12341); adder.Sum() != 12341 {
+			t.Errorf("Uint64Adder(%d) logic is wrong", ty)
+		}
+	}
+}
+
+func TestUint64AdderWrap(t *testing.T) {
+	for _, ty := range uint64AdderTypes {
+		adder := NewUint64Adder(ty)
+		adder.Store(math.MaxUint64)
+		adder.Add(2)
+		if adder.Sum() != 1 {
+			t.Errorf("Uint64Adder(%d) must wrap around", ty)
+		}
+		adder.Reset()
+		adder.Dec()
+		if adder.Sum() != math.MaxUint64 {
+			t.Errorf("Uint64Adder(%d) must wrap around", ty)
+		}
+		if adder.(interface{ Overflowed() bool }).Overflowed() {
+			t.Errorf("Uint64Adder(%d) must not report overflow without check", ty)
+		}
+	}
+}
+
+func TestUint64AdderOverflowCheck(t *testing.T) {
+	for _, ty := range uint64AdderTypes {
+		var calls int32
+		adder := NewUint64Adder(ty, WithOverflowCheck(func() { atomic.AddInt32(&calls, 1) }))
+		checker := adder.(interface{ Overflowed() bool })
+
+		adder.Add(math.MaxUint64 - 1)
+		adder.Inc()
+		adder.Sum()
+		if checker.Overflowed() || atomic.LoadInt32(&calls) != 0 {
+			t.Errorf("Uint64Adder(%d) must not report overflow", ty)
+		}
+
+		adder.Inc()
+		adder.Sum()
+		if !checker.Overflowed() || atomic.LoadInt32(&calls) == 0 {
+			t.Errorf("Uint64Adder(%d) must report overflow", ty)
+		}
+
+		// sticky
+		adder.Reset()
+		if !checker.Overflowed() {
+			t.Errorf("Uint64Adder(%d) overflow flag must be sticky", ty)
+		}
+	}
+}
+
+func TestUint64AdderUnderflowCheck(t *testing.T) {
+	for _, ty := range uint64AdderTypes {
+		adder := NewUint64Adder(ty, WithOverflowCheck(nil))
+		checker := adder.(interface{ Overflowed() bool })
+
+		adder.Inc()
+		adder.Dec()
+		if checker.Overflowed() || adder.Sum() != 0 {
+			t.Errorf("Uint64Adder(%d) must not report overflow", ty)
+		}
+
+		adder.Dec()
+		if adder.Sum() != math.MaxUint64 || !checker.Overflowed() {
+			t.Errorf("Uint64Adder(%d) must report underflow", ty)
+		}
+	}
+}
+
+func TestUint64AdderOverflowCheckRace(t *testing.T) {
+	for _, ty := range uint64AdderTypes {
+		adder := NewUint64Adder(ty, WithOverflowCheck(nil))
+		checker := adder.(interface{ Overflowed() bool })
+		adder.Add(uint64(numRoutine))
+
+		// cells go below zero while the sum never does
+		var wg sync.WaitGroup
+		for i := 0; i < numRoutine; i++ {
+			wg.Add(1)
+			go func() {
+				for j := 0; j < delta/10; j++ {
+					adder.Dec()
+					adder.Inc()
+				}
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+
+		if adder.Sum() != uint64(numRoutine) || checker.Overflowed() {
+			t.Errorf("Uint64Adder(%d) logic is wrong", ty)
+		}
+	}
+}
+
+func TestJDKUint64AdderAggregatedOverflow(t *testing.T) {
+	var calls int32
+	adder := NewJDKUint64Adder(WithOverflowCheck(func() { atomic.AddInt32(&calls, 1) }))
+
+	// neither base nor cell wraps around, but the sum does
+	adder.Add(1 << 63)
+	adder.accumulate(getRandomInt(), 1<<63, nil, false, true)
+	if adder.Overflowed() {
+		t.Errorf("Overflow must not be reported before summing")
+	}
+
+	if adder.Sum() != 0 || !adder.Overflowed() || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Aggregated overflow must be reported")
+	}
+}
+
+func TestRandomCellUint64AdderAggregatedOverflow(t *testing.T) {
+	adder := NewRandomCellUint64Adder(WithOverflowCheck(nil))
+	adder.cells[1] = 1 << 63
+	adder.cells[2] = 1 << 63
+	if adder.SumAndReset() != 0 || !adder.Overflowed() {
+		t.Errorf("Aggregated overflow must be reported")
+	}
+}
+
+func TestWide(t *testing.T) {
+	var w wide
+	addWide(&w, int64(math.MaxInt64))
+	addWide(&w, int64(1))
+	if _, ok := narrowBig[int64](w.big()); ok {
+		t.Errorf("Wide sum must exceed int64")
+	}
+	addWide(&w, int64(-2))
+	if v, ok := narrowBig[int64](w.big()); !ok || v != math.MaxInt64-1 {
+		t.Errorf("Wide sum is wrong: %d", v)
+	}
+
+	w = wide{}
+	addWide(&w, int32(math.MinInt32))
+	addWide(&w, int32(-1))
+	if _, ok := narrowBig[int32](w.big()); ok {
+		t.Errorf("Wide sum must exceed int32")
+	}
+
+	w = wide{}
+	addWide(&w, uint64(math.MaxUint64))
+	if v, ok := narrowBig[uint64](w.big()); !ok || v != math.MaxUint64 {
+		t.Errorf("Wide sum is wrong: %d", v)
+	}
+	addWide(&w, uint64(1))
+	if _, ok := narrowBig[uint64](w.big()); ok {
+		t.Errorf("Wide sum must exceed uint64")
+	}
+}